@@ -61,6 +61,6 @@ func dyTest() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	dyClient.PublishImage(ctx, "test.jpg", "test", "test")
+	dyClient.PublishImage(ctx, "test.jpg", "test", "test", nil)
 
 }
@@ -4,8 +4,10 @@ go 1.24.6
 
 require (
 	github.com/PuerkitoBio/goquery v1.10.3
+	github.com/gorilla/websocket v1.5.3
 	github.com/tjfoc/gmsm v1.4.1
 	golang.org/x/crypto v0.37.0
+	google.golang.org/protobuf v1.36.9
 	resty.dev/v3 v3.0.0-beta.3
 )
 
@@ -1,7 +1,7 @@
 package bd
 
 import (
-	"crypto/ecdh"
+	"crypto/ecdsa"
 	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/x509"
@@ -100,9 +100,13 @@ func (b *BDSigner) DeriveECDHKey(privateKeyStr string, cert string) ([]byte, err
 		return nil, fmt.Errorf("解析私钥失败: %w", err)
 	}
 
-	ecdhPrivKey, ok := privateKey.(*ecdh.PrivateKey)
+	ecdsaPrivKey, ok := privateKey.(*ecdsa.PrivateKey)
 	if !ok {
-		return nil, fmt.Errorf("私钥不是有效的 ECDH 私钥")
+		return nil, fmt.Errorf("私钥不是有效的 EC 私钥")
+	}
+	ecdhPrivKey, err := ecdsaPrivKey.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("转换 ECDH 私钥失败: %w", err)
 	}
 
 	// 2. 加载对端的证书并提取公钥
@@ -115,9 +119,13 @@ func (b *BDSigner) DeriveECDHKey(privateKeyStr string, cert string) ([]byte, err
 		return nil, fmt.Errorf("解析证书失败: %w", err)
 	}
 
-	ecdhPubKey, ok := peerCert.PublicKey.(*ecdh.PublicKey)
+	ecdsaPubKey, ok := peerCert.PublicKey.(*ecdsa.PublicKey)
 	if !ok {
-		return nil, fmt.Errorf("公钥不是有效的 ECDH 公钥")
+		return nil, fmt.Errorf("证书公钥不是有效的 EC 公钥")
+	}
+	ecdhPubKey, err := ecdsaPubKey.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("转换 ECDH 公钥失败: %w", err)
 	}
 
 	// 3. 执行 ECDH 获取共享密钥
@@ -149,3 +157,29 @@ func (b *BDSigner) SignWithHmacSha256(message []byte) string {
 	// 计算并返回十六进制编码的签名
 	return hex.EncodeToString(mac.Sum(nil))
 }
+
+// HeaderBuilder 按 ticket-guard 版本生成请求头
+// BDSigner 对应网页端 version 2，BDTGKeyPair 对应 version 3
+type HeaderBuilder interface {
+	// Headers 返回请求 path 所需的 bd-ticket-guard-* 请求头，返回 nil 时不添加任何请求头
+	Headers(path string) (map[string]string, error)
+}
+
+// Headers 生成 version 2 的 ticket-guard 请求头
+func (b *BDSigner) Headers(path string) (map[string]string, error) {
+	if b == nil {
+		return nil, nil
+	}
+	clientData, err := b.BDSign(path)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"bd-ticket-guard-client-data":       clientData,
+		"bd-ticket-guard-web-sign-type":     "1",
+		"bd-ticket-guard-iteration-version": "1",
+		"bd-ticket-guard-ree-public-key":    b.reePublicKey,
+		"bd-ticket-guard-version":           "2",
+		"bd-ticket-guard-web-version":       "2",
+	}, nil
+}
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"sync"
 	"time"
 )
 
@@ -65,7 +66,7 @@ func genEcdsa() (manage *KeyManage, hexString string) {
 	//打印私钥
 	//fmt.Printf("Private Key len = %d: %x\n", len(priKey.D.Bytes()), priKey.D.Bytes())
 
-	hexString = priKey.D.Text(16)
+	hexString = fmt.Sprintf("%064x", priKey.D)
 
 	pubKeyBytes := elliptic.Marshal(elliptic.P256(), priKey.X, priKey.Y)
 
@@ -82,6 +83,15 @@ func genEcdsa() (manage *KeyManage, hexString string) {
 }
 
 func genCsr(priKey *ecdsa.PrivateKey) string {
+	m, err := csrPEM(priKey)
+	if err != nil {
+		log.Println(err)
+	}
+	return base64.StdEncoding.EncodeToString(m)
+}
+
+// csrPEM 生成 ticket-guard 使用的 PEM 格式 CSR
+func csrPEM(priKey *ecdsa.PrivateKey) ([]byte, error) {
 	subj := pkix.Name{
 		CommonName: "bd-ticket-guard",
 	}
@@ -91,20 +101,11 @@ func genCsr(priKey *ecdsa.PrivateKey) string {
 		SignatureAlgorithm: x509.ECDSAWithSHA256,
 	}
 
-	csrBytes, e := x509.CreateCertificateRequest(rand.Reader, &template, priKey)
-	if e != nil {
-		log.Println(e)
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &template, priKey)
+	if err != nil {
+		return nil, err
 	}
-
-	block := &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrBytes}
-
-	buf := new(bytes.Buffer)
-	pem.Encode(buf, block)
-	//pem.Encode(os.Stdout, &pem.Block{Type: "EC PRIVATE KEY", Bytes: x509Encoded})
-
-	m := pem.EncodeToMemory(block) // []byte
-
-	return base64.StdEncoding.EncodeToString(m)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrBytes}), nil
 }
 
 func genCert(priKey *ecdsa.PrivateKey) string {
@@ -172,6 +173,7 @@ type BDTGKeyPair struct {
 	ServerCert       string `json:"server_cert"`
 	ServerSn         string `json:"server_sn"`
 
+	mu        sync.Mutex
 	teeManage *KeyManage
 	reeManage *KeyManage
 }
@@ -226,7 +228,7 @@ func (bd *BDTGKeyPair) GetClientCert() string {
 }
 
 func (bd *BDTGKeyPair) GetPostCsr() string {
-	if bd.reeManage == nil || bd.teeManage.privateKey == nil {
+	if bd.reeManage == nil || bd.reeManage.privateKey == nil {
 		return ""
 	}
 	return genCsr(bd.reeManage.privateKey)
@@ -237,7 +239,7 @@ func (bd *BDTGKeyPair) GetClientData(path string) string {
 		bd.reeManage.privateKey == nil ||
 		bd.teeManage == nil ||
 		bd.teeManage.privateKey == nil {
-		//return ""
+		return ""
 	}
 	if len(bd.TsSignRee) < 10 || len(bd.TsSignTee) < 10 {
 		return ""
@@ -264,81 +266,32 @@ func (bd *BDTGKeyPair) GetClientData(path string) string {
 	return base64.StdEncoding.EncodeToString(clientData)
 }
 
-// func Config(b *DouyinApi) flurl.Config {
-// 	return func(rb *flurl.Builder) {
-// 		u, err := rb.URL()
-// 		if err != nil {
-// 			rb.SetErr(err)
-// 			return
-// 		}
-// 		urlPath := u.Path
-
-// 		k := &BDTGKeyPair{
-// 			TsSignRee:        b.TsSignRee,
-// 			TsSignTee:        b.TsSignTee,
-// 			ReePrivateKeyHex: b.ReePrivateKeyHex,
-// 			XTTToken:         b.Token,
-// 		}
-
-// 		// 解析URL
-// 		n, _ := strconv.Atoi(b.BuildNumber)
-// 		if n < 298014 {
-// 			//logger.Printf("app_version < 29 账号：%s", b.UserName)
-// 			return
-// 		} else if k.ReePrivateKeyHex == "" || k.TsSignRee == "" || k.TsSignTee == "" {
-// 			log.Println("BDSign Key Error NULL 账号：", b.UserName)
-// 			//rb.SetErr(fmt.Errorf("BDSign Key Error 账号：%s", b.UserName))
-// 			return
-// 		}
-// 		if !k.BuildTeeKey() || !k.BuildReeKey() {
-// 			rb.SetErr(fmt.Errorf("BDSign Build Key Error 账号：%s", b.UserName))
-// 			return
-// 		}
-// 		headers := map[string]string{
-// 			"bd-ticket-guard-client-data":       k.GetClientData(urlPath),
-// 			"bd-ticket-guard-ree-public-key":    k.GetReePubKeyBase64String(),
-// 			"bd-ticket-guard-client-cert":       k.GetClientCert(),
-// 			"bd-ticket-guard-version":           "3",
-// 			"bd-ticket-guard-iteration-version": "2",
-// 		}
-// 		// 将headers添加到请求中
-// 		for key, value := range headers {
-// 			if value == "" {
-// 				rb.SetErr(fmt.Errorf("BDSign Key Error 账号：%s key:%s", b.UserName, key))
-// 				return
-// 			}
-// 			rb.Header(key, value)
-// 		}
-// 	}
-// }
-
-// func GetBdSign(b *DouyinApi, u string) map[string]string {
-// 	k := &BDTGKeyPair{
-// 		TsSignRee:        b.TsSignRee,
-// 		TsSignTee:        b.TsSignTee,
-// 		ReePrivateKeyHex: b.ReePrivateKeyHex,
-// 		XTTToken:         b.Token,
-// 	}
-
-// 	// 解析URL
-// 	n, _ := strconv.Atoi(b.BuildNumber)
-// 	if n < 298014 {
-// 		//logger.Printf("app_version < 29 账号：%s", b.UserName)
-// 		return nil
-// 	} else if k.ReePrivateKeyHex == "" || k.TsSignRee == "" || k.TsSignTee == "" {
-// 		log.Println("BDSign Key Error NULL 账号：", b.UserName)
-// 		//rb.SetErr(fmt.Errorf("BDSign Key Error 账号：%s", b.UserName))
-// 		return nil
-// 	}
-// 	if !k.BuildTeeKey() || !k.BuildReeKey() {
-// 		return nil
-// 	}
-// 	headers := map[string]string{
-// 		"bd-ticket-guard-client-data":       k.GetClientData(u),
-// 		"bd-ticket-guard-ree-public-key":    k.GetReePubKeyBase64String(),
-// 		"bd-ticket-guard-client-cert":       k.GetClientCert(),
-// 		"bd-ticket-guard-version":           "3",
-// 		"bd-ticket-guard-iteration-version": "2",
-// 	}
-// 	return headers
-// }
+// Headers 生成 version 3 的 ticket-guard 请求头，首次调用时构建 TEE/REE 密钥
+func (bd *BDTGKeyPair) Headers(path string) (map[string]string, error) {
+	if bd == nil {
+		return nil, nil
+	}
+	bd.mu.Lock()
+	if bd.teeManage == nil && !bd.BuildTeeKey() {
+		bd.mu.Unlock()
+		return nil, fmt.Errorf("build tee key failed")
+	}
+	if bd.reeManage == nil && !bd.BuildReeKey() {
+		bd.mu.Unlock()
+		return nil, fmt.Errorf("build ree key failed")
+	}
+	bd.mu.Unlock()
+	headers := map[string]string{
+		"bd-ticket-guard-client-data":       bd.GetClientData(path),
+		"bd-ticket-guard-ree-public-key":    bd.GetReePubKeyBase64String(),
+		"bd-ticket-guard-client-cert":       bd.GetClientCert(),
+		"bd-ticket-guard-version":           "3",
+		"bd-ticket-guard-iteration-version": "2",
+	}
+	for key, value := range headers {
+		if value == "" {
+			return nil, fmt.Errorf("ticket-guard header %s is empty", key)
+		}
+	}
+	return headers, nil
+}
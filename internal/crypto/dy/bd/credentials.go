@@ -0,0 +1,135 @@
+package bd
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+)
+
+// DefaultServerCert 网页端 ticket-guard 服务端证书 (CN=ecies-server)，有效期至 2069 年
+const DefaultServerCert = `-----BEGIN CERTIFICATE-----
+MIIEfTCCBCKgAwIBAgIUXWdS2tzmSoewCWfKFyiWMrJqs/0wCgYIKoZIzj0EAwIw
+MTELMAkGA1UEBhMCQ04xIjAgBgNVBAMMGXRpY2tldF9ndWFyZF9jYV9lY2RzYV8y
+NTYwIBcNMjIxMTE4MDUyMDA2WhgPMjA2OTEyMzExNjAwMDBaMCQxCzAJBgNVBAYT
+AkNOMRUwEwYDVQQDEwxlY2llcy1zZXJ2ZXIwWTATBgcqhkjOPQIBBggqhkjOPQMB
+BwNCAASE2llDPlfc8Rq+5J5HXhg4edFjPnCF3Ua7JBoiE/foP9m7L5ELIcvxCgEx
+aRCHbQ8kCCK/ArZ4FX/qCobZAkToo4IDITCCAx0wDgYDVR0PAQH/BAQDAgWgMDEG
+A1UdJQQqMCgGCCsGAQUFBwMBBggrBgEFBQcDAgYIKwYBBQUHAwMGCCsGAQUFBwME
+MCkGA1UdDgQiBCABydxqGrVEHhtkCWTb/vicGpDZPFPDxv82wiuywUlkBDArBgNV
+HSMEJDAigCAypWfqjmRIEo3MTk1Ae3MUm0dtU3qk0YDXeZSXeyJHgzCCAZQGCCsG
+AQUFBwEBBIIBhjCCAYIwRgYIKwYBBQUHMAGGOmh0dHA6Ly9uZXh1cy1wcm9kdWN0
+aW9uLmJ5dGVkYW5jZS5jb20vYXBpL2NlcnRpZmljYXRlL29jc3AwRgYIKwYBBQUH
+MAGGOmh0dHA6Ly9uZXh1cy1wcm9kdWN0aW9uLmJ5dGVkYW5jZS5uZXQvYXBpL2Nl
+cnRpZmljYXRlL29jc3AwdwYIKwYBBQUHMAKGa2h0dHA6Ly9uZXh1cy1wcm9kdWN0
+aW9uLmJ5dGVkYW5jZS5jb20vYXBpL2NlcnRpZmljYXRlL2Rvd25sb2FkLzQ4RjlD
+MEU3QjBDNUE3MDVCOTgyQkU1NTE3MDVGNjQ1QzhDODc4QTguY3J0MHcGCCsGAQUF
+BzAChmtodHRwOi8vbmV4dXMtcHJvZHVjdGlvbi5ieXRlZGFuY2UubmV0L2FwaS9j
+ZXJ0aWZpY2F0ZS9kb3dubG9hZC80OEY5QzBFN0IwQzVBNzA1Qjk4MkJFNTUxNzA1
+RjY0NUM4Qzg3OEE4LmNydDCB5wYDVR0fBIHfMIHcMGygaqBohmZodHRwOi8vbmV4
+dXMtcHJvZHVjdGlvbi5ieXRlZGFuY2UuY29tL2FwaS9jZXJ0aWZpY2F0ZS9jcmwv
+NDhGOUMwRTdCMEM1QTcwNUI5ODJCRTU1MTcwNUY2NDVDOEM4NzhBOC5jcmwwbKBq
+oGiGZmh0dHA6Ly9uZXh1cy1wcm9kdWN0aW9uLmJ5dGVkYW5jZS5uZXQvYXBpL2Nl
+cnRpZmljYXRlL2NybC80OEY5QzBFN0IwQzVBNzA1Qjk4MkJFNTUxNzA1RjY0NUM4
+Qzg3OEE4LmNybDAKBggqhkjOPQQDAgNJADBGAiEAqMjT5ADMdGMeaImoJK4J9jzE
+LqZ573rNjsT3k14pK50CIQCLpWHVKWi71qqqrMjiSDvUhpyO1DpTPRHlavPRuaNm
+ww==
+-----END CERTIFICATE-----`
+
+// Credentials bd-ticket-guard 的完整凭证，可 JSON 序列化后持久化保存
+//
+// 生命周期:
+//  1. NewCredentials 生成 P-256 密钥对与 CSR
+//  2. 登录等 passport 请求携带 ClientCSR() 作为 bd-ticket-guard-client-csr 请求头
+//  3. 响应头 bd-ticket-guard-server-data 交给 ApplyServerData，得到 ts_sign、ticket 与 client_cert
+//  4. NewBDSignerFromCredentials 构造 BDSigner
+type Credentials struct {
+	EcPrivateKey string `json:"ec_private_key"`
+	EcPublicKey  string `json:"ec_public_key"`
+	EcCSR        string `json:"ec_csr"`
+	TsSign       string `json:"ts_sign,omitempty"`
+	Ticket       string `json:"ticket,omitempty"`
+	ClientCert   string `json:"client_cert,omitempty"`
+	ServerCert   string `json:"server_cert"`
+	CreateTime   int64  `json:"create_time,omitempty"`
+}
+
+// NewCredentials 生成新的 P-256 密钥对与 CSR，服务端证书使用 DefaultServerCert
+// 密钥与 CSR 与 BDTGKeyPair 使用同一套生成逻辑 (genEcdsa、csrPEM)
+func NewCredentials() (*Credentials, error) {
+	manage, _ := genEcdsa()
+	if manage == nil {
+		return nil, fmt.Errorf("生成密钥失败")
+	}
+	key := manage.privateKey
+	privDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("序列化私钥失败: %w", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("序列化公钥失败: %w", err)
+	}
+	csr, err := csrPEM(key)
+	if err != nil {
+		return nil, fmt.Errorf("生成 CSR 失败: %w", err)
+	}
+	return &Credentials{
+		EcPrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})),
+		EcPublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})),
+		EcCSR:        string(csr),
+		ServerCert:   DefaultServerCert,
+	}, nil
+}
+
+// ClientCSR 返回 bd-ticket-guard-client-csr 请求头的值
+func (c *Credentials) ClientCSR() string {
+	return base64.StdEncoding.EncodeToString([]byte(c.EcCSR))
+}
+
+// ServerData bd-ticket-guard-server-data 响应头解码后的内容
+type ServerData struct {
+	Ticket     string `json:"ticket"`
+	TsSign     string `json:"ts_sign"`
+	ClientCert string `json:"client_cert"`
+	LogID      string `json:"log_id"`
+	CreateTime int64  `json:"create_time"`
+}
+
+// ApplyServerData 解析 bd-ticket-guard-server-data 响应头并保存 ticket、ts_sign 与 client_cert
+func (c *Credentials) ApplyServerData(header string) error {
+	raw, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return fmt.Errorf("解码 server data 失败: %w", err)
+	}
+	var data ServerData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("解析 server data 失败: %w", err)
+	}
+	if data.Ticket == "" || data.TsSign == "" {
+		return fmt.Errorf("server data 缺少 ticket 或 ts_sign")
+	}
+	c.Ticket = data.Ticket
+	c.TsSign = data.TsSign
+	c.ClientCert = data.ClientCert
+	c.CreateTime = data.CreateTime
+	return nil
+}
+
+// Registered 是否已获取 ticket 与 ts_sign
+func (c *Credentials) Registered() bool {
+	return c.Ticket != "" && c.TsSign != ""
+}
+
+// NewBDSignerFromCredentials 使用已注册的凭证构造 BDSigner
+func NewBDSignerFromCredentials(c *Credentials) (*BDSigner, error) {
+	if !c.Registered() {
+		return nil, fmt.Errorf("凭证尚未注册，缺少 ticket 或 ts_sign")
+	}
+	cert := c.ServerCert
+	if cert == "" {
+		cert = DefaultServerCert
+	}
+	return NewBDSigner(c.TsSign, c.Ticket, c.EcPrivateKey, c.EcPublicKey, cert)
+}
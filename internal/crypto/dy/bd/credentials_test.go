@@ -0,0 +1,105 @@
+package bd
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+)
+
+func TestCredentialsLifecycle(t *testing.T) {
+	creds, err := NewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewBDSignerFromCredentials(creds); err == nil {
+		t.Fatal("expected error for unregistered credentials")
+	}
+
+	serverData, _ := json.Marshal(ServerData{
+		Ticket:     "hash.test",
+		TsSign:     "ts.2.test",
+		ClientCert: "pub.test",
+		CreateTime: 1753870469,
+	})
+	if err := creds.ApplyServerData(base64.StdEncoding.EncodeToString(serverData)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 序列化后恢复
+	saved, err := json.Marshal(creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored Credentials
+	if err := json.Unmarshal(saved, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := NewBDSignerFromCredentials(&restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signer.derivedKey) != 32 {
+		t.Fatalf("expected 32 byte derived key, got %d", len(signer.derivedKey))
+	}
+
+	header, err := signer.BDSign("/aweme/v1/web/commit/item/digg/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data BDTicketGuardClientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.TsSign != "ts.2.test" || data.ReqContent != "ticket,path,timestamp" || data.ReqSign == "" {
+		t.Errorf("unexpected client data: %+v", data)
+	}
+}
+
+func TestNewCredentialsCSR(t *testing.T) {
+	creds, err := NewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode([]byte(creds.EcCSR))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		t.Fatalf("EcCSR is not a PEM CSR: %q", creds.EcCSR)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		t.Errorf("CSR signature: %v", err)
+	}
+
+	// CSR 与 BDTGKeyPair 的 genCsr 同源，公钥与凭证中的公钥一致
+	pubBlock, _ := pem.Decode([]byte(creds.EcPublicKey))
+	pub, err := x509.ParsePKIXPublicKey(pubBlock.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !csr.PublicKey.(*ecdsa.PublicKey).Equal(pub) {
+		t.Error("CSR public key does not match EcPublicKey")
+	}
+	manage, _ := genEcdsa()
+	other, _ := base64.StdEncoding.DecodeString(genCsr(manage.privateKey))
+	otherBlock, _ := pem.Decode(other)
+	otherCSR, err := x509.ParseCertificateRequest(otherBlock.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if csr.Subject.String() != otherCSR.Subject.String() || csr.SignatureAlgorithm != otherCSR.SignatureAlgorithm {
+		t.Errorf("credentials CSR %s/%s differs from genCsr %s/%s", csr.Subject, csr.SignatureAlgorithm, otherCSR.Subject, otherCSR.SignatureAlgorithm)
+	}
+	if got, want := creds.ClientCSR(), base64.StdEncoding.EncodeToString([]byte(creds.EcCSR)); got != want {
+		t.Errorf("ClientCSR() = %q, want base64 of EcCSR", got)
+	}
+}
@@ -0,0 +1,67 @@
+package bd
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestHeaderBuilderVersions(t *testing.T) {
+	creds, err := NewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds.Ticket, creds.TsSign = "hash.test", "ts.2.test"
+	v2, err := NewBDSignerFromCredentials(creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, reeHex := genEcdsa()
+	v3 := &BDTGKeyPair{
+		TsSignRee:        "ts.2.ree-sign-test",
+		TsSignTee:        "ts.2.tee-sign-test",
+		ReePrivateKeyHex: reeHex,
+		XTTToken:         "token",
+	}
+
+	wantPub, err := GetPubKeyBase64(creds.EcPrivateKey, creds.EcPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		builder HeaderBuilder
+		version string
+		pubKey  string
+	}{
+		{v2, "2", wantPub},
+		{v3, "3", ""},
+	} {
+		headers, err := tc.builder.Headers("/aweme/v1/web/commit/item/digg/")
+		if err != nil {
+			t.Fatalf("v%s: %v", tc.version, err)
+		}
+		if got := headers["bd-ticket-guard-version"]; got != tc.version {
+			t.Errorf("expected version %s, got %s", tc.version, got)
+		}
+		pub := headers["bd-ticket-guard-ree-public-key"]
+		if tc.pubKey != "" && pub != tc.pubKey {
+			t.Errorf("v%s: unexpected ree public key %s", tc.version, pub)
+		}
+		raw, err := base64.StdEncoding.DecodeString(pub)
+		if err != nil || len(raw) != 65 || raw[0] != 0x04 {
+			t.Errorf("v%s: ree public key is not an uncompressed P-256 point: %q", tc.version, pub)
+		}
+		if headers["bd-ticket-guard-client-data"] == "" {
+			t.Errorf("v%s: empty client data", tc.version)
+		}
+	}
+	if headers, _ := v3.Headers("/"); headers["bd-ticket-guard-client-cert"] == "" {
+		t.Error("v3: empty client cert")
+	}
+
+	var nilSigner *BDSigner
+	if headers, err := nilSigner.Headers("/"); headers != nil || err != nil {
+		t.Errorf("nil signer should produce no headers, got %v, %v", headers, err)
+	}
+}
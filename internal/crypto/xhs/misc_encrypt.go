@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash/crc32"
+	"math/big"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -204,10 +205,11 @@ func (m *MiscEncrypt) X_B3_TraceID() string {
 }
 
 // SearchID 生成 search_id
+// 与网页端一致: ((BigInt(Date.now()) << 64n) + BigInt(Math.ceil(2147483646 * Math.random()))).toString(36)
 func (m *MiscEncrypt) SearchID() string {
-	e := time.Now().UnixNano() / 1e6 << 64 // This shift might be problematic for int64, check Python's behavior
-	t := rand.Int63n(2147483646)           // Max int for Python's random.uniform(0, 2147483646)
-	return m.cfd.Base36Encode(e + t)
+	e := new(big.Int).Lsh(big.NewInt(time.Now().UnixMilli()), 64)
+	t := big.NewInt(rand.Int63n(2147483646) + 1)
+	return e.Add(e, t).Text(36)
 }
 
 // X_Xray_TraceID 生成 x_xray_traceid
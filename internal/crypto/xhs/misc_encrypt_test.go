@@ -0,0 +1,26 @@
+package xhs
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestSearchID(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id := NewMiscEncrypt().SearchID()
+	after := time.Now().UnixMilli()
+
+	n, ok := new(big.Int).SetString(id, 36)
+	if !ok {
+		t.Fatalf("SearchID() = %q, not a base36 number", id)
+	}
+	ts := new(big.Int).Rsh(n, 64).Int64()
+	if ts < before || ts > after {
+		t.Errorf("SearchID() timestamp = %d, want between %d and %d", ts, before, after)
+	}
+	r := new(big.Int).And(n, new(big.Int).SetUint64(^uint64(0))).Int64()
+	if r < 1 || r > 2147483646 {
+		t.Errorf("SearchID() random part = %d, want in [1, 2147483646]", r)
+	}
+}
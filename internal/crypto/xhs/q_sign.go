@@ -47,23 +47,27 @@ func getObjectKeys(m map[string]string) []string {
 	return keys
 }
 
-// obj2str converts a map to a URL-encoded string with sorted keys.
-// The keys are lowercased, and the values are URL-encoded.
+// obj2str converts a map to a URL-encoded string sorted by lowercased key.
+// Keys are lowercased and both keys and values are encoded like JS encodeURIComponent.
 func obj2str(m map[string]string) string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
+	lower := make(map[string]string, len(m))
+	for k, v := range m {
+		lower[strings.ToLower(k)] = v
 	}
-	sort.Strings(keys)
+	keys := getObjectKeys(lower)
 
 	var pairs []string
 	for _, k := range keys {
-		val := m[k]
-		pairs = append(pairs, fmt.Sprintf("%s=%s", strings.ToLower(k), url.QueryEscape(val)))
+		pairs = append(pairs, camSafeURLEncode(k)+"="+camSafeURLEncode(lower[k]))
 	}
 	return strings.Join(pairs, "&")
 }
 
+// camSafeURLEncode encodes s like encodeURIComponent with !'()* also escaped.
+func camSafeURLEncode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
 // hmacSha1 computes the HMAC-SHA1 hash of a message with a key and returns it as a hex string.
 func hmacSha1(message, key string) string {
 	h := hmac.New(sha1.New, []byte(key))
@@ -107,14 +111,15 @@ func GetQSignAuth(opts QSignAuthOptions) (string, error) {
 	}
 
 	// Time calculation
-	startTime := time.Now().Unix()
-	expires := opts.Expires
-	if expires == 0 {
-		expires = 900 // Default 900 seconds
+	signTime := opts.KeyTime
+	if signTime == "" {
+		startTime := time.Now().Unix() + opts.SystemClockOffset
+		expires := opts.Expires
+		if expires == 0 {
+			expires = 900 // Default 900 seconds
+		}
+		signTime = fmt.Sprintf("%d;%d", startTime, startTime+expires)
 	}
-	endTime := startTime + expires
-
-	signTime := fmt.Sprintf("%d;%d", startTime, endTime)
 	keyTime := signTime
 
 	// Get lists of header and query param keys
@@ -124,7 +129,7 @@ func GetQSignAuth(opts QSignAuthOptions) (string, error) {
 	// Signature calculation
 	signKey := hmacSha1(keyTime, opts.SecretKey)
 
-	httpString := fmt.Sprintf("%s\n%s\n%s\n%s",
+	httpString := fmt.Sprintf("%s\n%s\n%s\n%s\n",
 		method,
 		pathname,
 		obj2str(query),
@@ -0,0 +1,49 @@
+package xhs
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestGetQSignAuth(t *testing.T) {
+	auth, err := GetQSignAuth(QSignAuthOptions{
+		SecretId:  "null",
+		SecretKey: "null",
+		KeyTime:   "1755245595;1755331995",
+		Method:    "PUT",
+		Pathname:  "/spectrum/7vynuOPF72T6CexEgu_Hcy5c6NcUYaa1DU92in4JeItlMu4",
+		Headers: map[string]string{
+			"Host":           "ros-upload-d4.xhscdn.com",
+			"Content-Length": "16368",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 按 COS 签名规范独立计算
+	sum := func(key, msg string) string {
+		h := hmac.New(sha1.New, []byte(key))
+		h.Write([]byte(msg))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+	h := sha1.Sum([]byte("put\n/spectrum/7vynuOPF72T6CexEgu_Hcy5c6NcUYaa1DU92in4JeItlMu4\n\ncontent-length=16368&host=ros-upload-d4.xhscdn.com\n"))
+	stringToSign := "sha1\n1755245595;1755331995\n" + hex.EncodeToString(h[:])
+	signature := sum(sum("null", "1755245595;1755331995"), stringToSign)
+
+	want := strings.Join([]string{
+		"q-sign-algorithm=sha1",
+		"q-ak=null",
+		"q-sign-time=1755245595;1755331995",
+		"q-key-time=1755245595;1755331995",
+		"q-header-list=content-length;host",
+		"q-url-param-list=",
+		"q-signature=" + signature,
+	}, "&")
+	if auth != want {
+		t.Errorf("GetQSignAuth() = %s, want %s", auth, want)
+	}
+}
@@ -40,7 +40,6 @@ func BuildSignature(dValue, a1Value, xsecAppID, contentString string) string {
 }
 
 func Sign(method, uri, a1, xsecAppid string, params url.Values) string {
-	signatureData := NewSignatureDataTemplate()
 	contentString := ""
 	if strings.ToUpper(method) == "GET" {
 		// 排序
@@ -59,7 +58,17 @@ func Sign(method, uri, a1, xsecAppid string, params url.Values) string {
 		payload, _ := json.Marshal(params)
 		contentString = uri + string(payload)
 	}
+	return signContent(contentString, a1, xsecAppid)
+}
 
+// SignJSON 为 POST 请求生成 x-s，签名内容为 uri 与原始 JSON 请求体的拼接
+// body 必须与实际发送的请求体逐字节一致
+func SignJSON(uri, a1, xsecAppid string, body []byte) string {
+	return signContent(uri+string(body), a1, xsecAppid)
+}
+
+func signContent(contentString, a1, xsecAppid string) string {
+	signatureData := NewSignatureDataTemplate()
 	dValue := GenerateDValue(contentString)
 	signatureData.X1 = xsecAppid
 	signatureData.X3 = X3Prefix + BuildSignature(dValue, a1, xsecAppid, contentString)
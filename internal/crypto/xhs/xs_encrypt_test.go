@@ -1,9 +1,16 @@
 package xhs
 
 import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -77,6 +84,98 @@ func TestSign(t *testing.T) {
 	})
 }
 
+// decodeXYS 还原 signContent 的编码过程，返回签名 JSON 与 x3 中的明文字节
+func decodeXYS(t *testing.T, signature string) (*SignatureDataTemplate, []byte) {
+	t.Helper()
+	if !strings.HasPrefix(signature, XYSPrefix) {
+		t.Fatalf("signature %q missing prefix %q", signature, XYSPrefix)
+	}
+	var std strings.Builder
+	for _, c := range strings.TrimPrefix(signature, XYSPrefix) {
+		if idx := strings.IndexRune(CustomBase64Alphabet, c); idx != -1 {
+			std.WriteByte(StandardBase64Alphabet[idx])
+		} else {
+			std.WriteRune(c)
+		}
+	}
+	raw, err := base64.StdEncoding.DecodeString(std.String())
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	var data SignatureDataTemplate
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("decode signature json: %v", err)
+	}
+	if !strings.HasPrefix(data.X3, X3Prefix) {
+		t.Fatalf("x3 = %q, want prefix %q", data.X3, X3Prefix)
+	}
+
+	num := new(big.Int)
+	for _, c := range strings.TrimPrefix(data.X3, X3Prefix) {
+		idx := strings.IndexRune(Base58Alphabet[:Base58Base], c)
+		if idx == -1 {
+			t.Fatalf("x3 contains invalid base58 char %q", c)
+		}
+		num.Mul(num, big.NewInt(Base58Base))
+		num.Add(num, big.NewInt(int64(idx)))
+	}
+	return &data, XorTransformArray(num.Bytes())
+}
+
+// TestSignJSON 确认 POST 签名基于原始请求体，而非 url.Values 的 JSON 编码
+func TestSignJSON(t *testing.T) {
+	const (
+		uri   = "/api/sns/web/v1/feed"
+		a1    = "1989d69b653ej0uroayuyvsm4vdy26twup2h6vzan50000384309"
+		appID = "xhs-pc-web"
+	)
+	body := []byte(`{"source_note_id":"64b7a0f4000000001c00f0a1","image_formats":["jpg","webp","avif"]}`)
+	data, payload := decodeXYS(t, SignJSON(uri, a1, appID, body))
+
+	if data.X0 != "4.2.2" || data.X1 != appID || data.X2 != "Windows" || data.X4 != "object" {
+		t.Errorf("signature fields = %+v", data)
+	}
+
+	// 版本(4) 随机数(4) 时间戳(8) 启动时间戳(8) 固定值(4+4) 内容长度(4) md5 前 8 字节(8)，之后为 a1 与 appID
+	if len(payload) < 44 {
+		t.Fatalf("x3 payload too short: %d bytes", len(payload))
+	}
+	if !bytes.Equal(payload[:4], VersionBytes) {
+		t.Errorf("version bytes = %v, want %v", payload[:4], VersionBytes)
+	}
+	if got := binary.LittleEndian.Uint32(payload[24:28]); got != FixedIntValue1 {
+		t.Errorf("fixed value 1 = %d, want %d", got, FixedIntValue1)
+	}
+	if got := binary.LittleEndian.Uint32(payload[28:32]); got != FixedIntValue2 {
+		t.Errorf("fixed value 2 = %d, want %d", got, FixedIntValue2)
+	}
+
+	content := uri + string(body)
+	if got := binary.LittleEndian.Uint32(payload[32:36]); got != uint32(len(content)) {
+		t.Errorf("content length = %d, want %d", got, len(content))
+	}
+	xorKey := payload[4]
+	sum := md5.Sum([]byte(content))
+	for i := 0; i < 8; i++ {
+		if payload[36+i]^xorKey != sum[i] {
+			t.Fatalf("md5 prefix = %x, want %x", payload[36:44], sum[:8])
+		}
+	}
+
+	rest := payload[44:]
+	for _, want := range []string{a1, appID} {
+		n := int(rest[0])
+		if got := string(rest[1 : 1+n]); got != want {
+			t.Errorf("length-prefixed field = %q, want %q", got, want)
+		}
+		rest = rest[1+n:]
+	}
+	// 环境字节的第二位为随机值
+	if len(rest) != len(EnvStaticBytes)+1 || rest[0] != EnvStaticBytes[0] || !bytes.Equal(rest[2:], EnvStaticBytes[1:]) {
+		t.Errorf("environment bytes = %v", rest)
+	}
+}
+
 // GenerateDValue
 func TestGenerateDValue(t *testing.T) {
 	content := "/api/sns/web/v1/user_posted?num=30&cursor=&user_id=5d5c36ab0000000001008656&image_formats=jpg,webp,avif"
@@ -0,0 +1,426 @@
+// Package media 提供不依赖 ffprobe 的媒体文件元数据解析
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+// ErrNoMoov 文件中没有 moov 盒子，不是有效的 MP4/MOV 文件
+var ErrNoMoov = errors.New("media: moov box not found")
+
+// maxMoovSize moov 盒子的大小上限，避免异常文件导致过量内存分配
+const maxMoovSize = 256 << 20
+
+// VideoTrack 视频轨道信息
+type VideoTrack struct {
+	// Codec 编码格式，如 AVC、HEVC
+	Codec string
+	// Width、Height 编码宽高，未应用旋转
+	Width  int
+	Height int
+	// Rotation 顺时针旋转角度: 0、90、180、270
+	Rotation  int
+	Duration  time.Duration
+	FrameRate float64
+	// Bitrate 平均码率，单位 bit/s
+	Bitrate int
+	// 色彩信息，如 BT.709，文件中没有 colr 盒子时为空
+	ColourPrimaries         string
+	TransferCharacteristics string
+	MatrixCoefficients      string
+}
+
+// DisplaySize 返回应用旋转后的显示宽高
+func (v *VideoTrack) DisplaySize() (int, int) {
+	if v.Rotation == 90 || v.Rotation == 270 {
+		return v.Height, v.Width
+	}
+	return v.Width, v.Height
+}
+
+// AudioTrack 音频轨道信息
+type AudioTrack struct {
+	// Codec 编码格式，如 AAC
+	Codec      string
+	Channels   int
+	SampleRate int
+	Duration   time.Duration
+	// Bitrate 平均码率，单位 bit/s
+	Bitrate int
+}
+
+// Info MP4/MOV 文件的元数据，Video、Audio 为第一条对应类型的轨道，不存在时为 nil
+type Info struct {
+	Duration time.Duration
+	Video    *VideoTrack
+	Audio    *AudioTrack
+}
+
+// ProbeFile 解析 MP4/MOV 文件的元数据
+func ProbeFile(path string) (*Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Probe(f)
+}
+
+// Probe 解析 MP4/MOV 数据的元数据，只读取 moov 盒子，跳过 mdat 等媒体数据
+func Probe(r io.ReadSeeker) (*Info, error) {
+	for {
+		typ, size, hdr, err := readBoxHeader(r)
+		if err == io.EOF {
+			return nil, ErrNoMoov
+		}
+		if err != nil {
+			return nil, err
+		}
+		if typ != "moov" {
+			if size == 0 {
+				return nil, ErrNoMoov
+			}
+			if _, err := r.Seek(size-hdr, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if size == 0 || size-hdr > maxMoovSize {
+			return nil, fmt.Errorf("media: invalid moov size %d", size)
+		}
+		moov := make([]byte, size-hdr)
+		if _, err := io.ReadFull(r, moov); err != nil {
+			return nil, err
+		}
+		return parseMoov(moov)
+	}
+}
+
+// readBoxHeader 读取盒子头，返回类型、包含头部的总大小 (0 表示延伸到文件末尾) 与头部长度
+func readBoxHeader(r io.Reader) (string, int64, int64, error) {
+	var buf [16]byte
+	if _, err := io.ReadFull(r, buf[:8]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return "", 0, 0, err
+	}
+	size := int64(binary.BigEndian.Uint32(buf[:4]))
+	typ := string(buf[4:8])
+	hdr := int64(8)
+	if size == 1 {
+		if _, err := io.ReadFull(r, buf[8:16]); err != nil {
+			return "", 0, 0, err
+		}
+		size = int64(binary.BigEndian.Uint64(buf[8:16]))
+		hdr = 16
+	}
+	if size != 0 && size < hdr {
+		return "", 0, 0, fmt.Errorf("media: invalid %q box size %d", typ, size)
+	}
+	return typ, size, hdr, nil
+}
+
+type box struct {
+	typ  string
+	data []byte
+}
+
+// boxes 拆分内存中连续的子盒子
+func boxes(b []byte) ([]box, error) {
+	var out []box
+	for len(b) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(b[:4]))
+		typ := string(b[4:8])
+		hdr := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(b))
+		case 1:
+			if len(b) < 16 {
+				return nil, fmt.Errorf("media: truncated %q box", typ)
+			}
+			size = binary.BigEndian.Uint64(b[8:16])
+			hdr = 16
+		}
+		if size < hdr || size > uint64(len(b)) {
+			return nil, fmt.Errorf("media: invalid %q box size %d", typ, size)
+		}
+		out = append(out, box{typ: typ, data: b[hdr:size]})
+		b = b[size:]
+	}
+	return out, nil
+}
+
+// child 返回第一个指定类型的子盒子
+func child(b []byte, typ string) []byte {
+	children, err := boxes(b)
+	if err != nil {
+		return nil
+	}
+	for _, c := range children {
+		if c.typ == typ {
+			return c.data
+		}
+	}
+	return nil
+}
+
+// path 按路径逐层查找子盒子
+func path(b []byte, types ...string) []byte {
+	for _, t := range types {
+		if b = child(b, t); b == nil {
+			return nil
+		}
+	}
+	return b
+}
+
+func parseMoov(moov []byte) (*Info, error) {
+	info := &Info{}
+	if mvhd := child(moov, "mvhd"); mvhd != nil {
+		timescale, duration, err := parseTimeHeader(mvhd)
+		if err != nil {
+			return nil, fmt.Errorf("media: mvhd: %w", err)
+		}
+		info.Duration = scaleDuration(duration, timescale)
+	}
+
+	traks, err := boxes(moov)
+	if err != nil {
+		return nil, err
+	}
+	for _, trak := range traks {
+		if trak.typ != "trak" {
+			continue
+		}
+		if err := parseTrak(info, trak.data); err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+func parseTrak(info *Info, trak []byte) error {
+	mdia := child(trak, "mdia")
+	hdlr := child(mdia, "hdlr")
+	if len(hdlr) < 12 {
+		return nil
+	}
+	handler := string(hdlr[8:12])
+	if handler == "vide" && info.Video != nil || handler == "soun" && info.Audio != nil {
+		return nil
+	}
+
+	var duration time.Duration
+	var timescale uint32
+	if mdhd := child(mdia, "mdhd"); mdhd != nil {
+		var d uint64
+		var err error
+		timescale, d, err = parseTimeHeader(mdhd)
+		if err != nil {
+			return fmt.Errorf("media: mdhd: %w", err)
+		}
+		duration = scaleDuration(d, timescale)
+	}
+
+	stbl := path(mdia, "minf", "stbl")
+	entry := sampleEntry(child(stbl, "stsd"))
+	bitrate := 0
+	if seconds := duration.Seconds(); seconds > 0 {
+		bitrate = int(math.Round(float64(sampleBytes(child(stbl, "stsz"))) * 8 / seconds))
+	}
+
+	switch handler {
+	case "vide":
+		v := &VideoTrack{Duration: duration, Bitrate: bitrate}
+		v.Rotation = trackRotation(child(trak, "tkhd"))
+		if samples, delta := sampleTiming(child(stbl, "stts")); delta > 0 {
+			v.FrameRate = float64(samples) * float64(timescale) / float64(delta)
+		}
+		if entry != nil {
+			v.Codec = codecName(entry.typ)
+			parseVisualSampleEntry(v, entry.data)
+		}
+		info.Video = v
+	case "soun":
+		a := &AudioTrack{Duration: duration, Bitrate: bitrate}
+		if entry != nil {
+			a.Codec = codecName(entry.typ)
+			if d := entry.data; len(d) >= 28 {
+				a.Channels = int(binary.BigEndian.Uint16(d[16:18]))
+				a.SampleRate = int(binary.BigEndian.Uint32(d[24:28]) >> 16)
+			}
+		}
+		info.Audio = a
+	}
+	return nil
+}
+
+// parseTimeHeader 解析 mvhd/mdhd 中的 timescale 与 duration
+func parseTimeHeader(b []byte) (uint32, uint64, error) {
+	if len(b) < 4 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	if b[0] == 1 {
+		if len(b) < 32 {
+			return 0, 0, io.ErrUnexpectedEOF
+		}
+		return binary.BigEndian.Uint32(b[20:24]), binary.BigEndian.Uint64(b[24:32]), nil
+	}
+	if len(b) < 20 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	return binary.BigEndian.Uint32(b[12:16]), uint64(binary.BigEndian.Uint32(b[16:20])), nil
+}
+
+func scaleDuration(d uint64, timescale uint32) time.Duration {
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(float64(d) / float64(timescale) * float64(time.Second))
+}
+
+// trackRotation 从 tkhd 的变换矩阵中读取旋转角度
+func trackRotation(tkhd []byte) int {
+	offset := 40
+	if len(tkhd) > 0 && tkhd[0] == 1 {
+		offset = 52
+	}
+	if len(tkhd) < offset+16 {
+		return 0
+	}
+	a := int32(binary.BigEndian.Uint32(tkhd[offset:]))
+	b := int32(binary.BigEndian.Uint32(tkhd[offset+4:]))
+	deg := int(math.Round(math.Atan2(float64(b), float64(a)) * 180 / math.Pi))
+	return (deg + 360) % 360
+}
+
+// sampleEntry 返回 stsd 中的第一个样本描述
+func sampleEntry(stsd []byte) *box {
+	if len(stsd) < 8 {
+		return nil
+	}
+	entries, err := boxes(stsd[8:])
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+	return &entries[0]
+}
+
+// visualSampleEntrySize VisualSampleEntry 固定字段的长度，其后为 avcC、colr 等子盒子
+const visualSampleEntrySize = 78
+
+func parseVisualSampleEntry(v *VideoTrack, d []byte) {
+	if len(d) < visualSampleEntrySize {
+		return
+	}
+	v.Width = int(binary.BigEndian.Uint16(d[24:26]))
+	v.Height = int(binary.BigEndian.Uint16(d[26:28]))
+
+	colr := child(d[visualSampleEntrySize:], "colr")
+	if len(colr) < 10 {
+		return
+	}
+	switch string(colr[:4]) {
+	case "nclx", "nclc":
+		v.ColourPrimaries = colourPrimaries[binary.BigEndian.Uint16(colr[4:6])]
+		v.TransferCharacteristics = transferCharacteristics[binary.BigEndian.Uint16(colr[6:8])]
+		v.MatrixCoefficients = matrixCoefficients[binary.BigEndian.Uint16(colr[8:10])]
+	}
+}
+
+// sampleTiming 返回 stts 中的样本总数与总时长 (以 mdhd timescale 为单位)
+func sampleTiming(stts []byte) (uint64, uint64) {
+	if len(stts) < 8 {
+		return 0, 0
+	}
+	n := int(binary.BigEndian.Uint32(stts[4:8]))
+	var samples, delta uint64
+	for i := 0; i < n && 8+i*8+8 <= len(stts); i++ {
+		e := stts[8+i*8:]
+		count := uint64(binary.BigEndian.Uint32(e[:4]))
+		samples += count
+		delta += count * uint64(binary.BigEndian.Uint32(e[4:8]))
+	}
+	return samples, delta
+}
+
+// sampleBytes 返回 stsz 中全部样本的总字节数
+func sampleBytes(stsz []byte) uint64 {
+	if len(stsz) < 12 {
+		return 0
+	}
+	size := uint64(binary.BigEndian.Uint32(stsz[4:8]))
+	count := int(binary.BigEndian.Uint32(stsz[8:12]))
+	if size != 0 {
+		return size * uint64(count)
+	}
+	var total uint64
+	for i := 0; i < count && 12+i*4+4 <= len(stsz); i++ {
+		total += uint64(binary.BigEndian.Uint32(stsz[12+i*4:]))
+	}
+	return total
+}
+
+func codecName(fourcc string) string {
+	switch fourcc {
+	case "avc1", "avc3":
+		return "AVC"
+	case "hvc1", "hev1":
+		return "HEVC"
+	case "av01":
+		return "AV1"
+	case "vp09":
+		return "VP9"
+	case "mp4a":
+		return "AAC"
+	case "ac-3":
+		return "AC-3"
+	case "ec-3":
+		return "E-AC-3"
+	case "Opus":
+		return "Opus"
+	default:
+		return fourcc
+	}
+}
+
+// colr 盒子中的色彩编码，取值见 ITU-T H.273
+var (
+	colourPrimaries = map[uint16]string{
+		1:  "BT.709",
+		4:  "BT.470 System M",
+		5:  "BT.601 PAL",
+		6:  "BT.601 NTSC",
+		7:  "SMPTE 240M",
+		9:  "BT.2020",
+		12: "Display P3",
+	}
+	transferCharacteristics = map[uint16]string{
+		1:  "BT.709",
+		6:  "BT.601",
+		7:  "SMPTE 240M",
+		8:  "Linear",
+		13: "sRGB/sYCC",
+		14: "BT.2020 (10-bit)",
+		15: "BT.2020 (12-bit)",
+		16: "PQ",
+		18: "HLG",
+	}
+	matrixCoefficients = map[uint16]string{
+		0:  "Identity",
+		1:  "BT.709",
+		5:  "BT.601",
+		6:  "BT.601",
+		7:  "SMPTE 240M",
+		9:  "BT.2020 non-constant",
+		10: "BT.2020 constant",
+	}
+)
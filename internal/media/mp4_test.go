@@ -0,0 +1,122 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func mkbox(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	b = append(b, typ...)
+	return append(b, body...)
+}
+
+func u16(v uint16) []byte { return binary.BigEndian.AppendUint16(nil, v) }
+func u32(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+
+// timeHeader 构建 version 0 的 mvhd/mdhd
+func timeHeader(typ string, timescale, duration uint32) []byte {
+	return mkbox(typ, u32(0), u32(0), u32(0), u32(timescale), u32(duration), make([]byte, 8))
+}
+
+func tkhd(a, b, c, d int32) []byte {
+	matrix := bytes.Join([][]byte{
+		u32(uint32(a)), u32(uint32(b)), u32(0),
+		u32(uint32(c)), u32(uint32(d)), u32(0),
+		u32(0), u32(0), u32(0x40000000),
+	}, nil)
+	return mkbox("tkhd", u32(0), make([]byte, 36), matrix, u32(0), u32(0))
+}
+
+func hdlr(handler string) []byte {
+	return mkbox("hdlr", u32(0), u32(0), []byte(handler), make([]byte, 12))
+}
+
+func trak(tkhdBox []byte, handler string, timescale, duration uint32, stsd, stts, stsz []byte) []byte {
+	stbl := mkbox("stbl", stsd, stts, stsz)
+	return mkbox("trak", tkhdBox, mkbox("mdia",
+		timeHeader("mdhd", timescale, duration),
+		hdlr(handler),
+		mkbox("minf", stbl),
+	))
+}
+
+func TestProbe(t *testing.T) {
+	// 1080x1920 HEVC，顺时针旋转 90 度，10 秒 300 帧，每帧 12500 字节
+	visual := bytes.Join([][]byte{
+		make([]byte, 6), u16(1), make([]byte, 16),
+		u16(1920), u16(1080),
+		u32(0x00480000), u32(0x00480000), u32(0), u16(1), make([]byte, 32), u16(0x18), u16(0xffff),
+	}, nil)
+	colr := mkbox("colr", []byte("nclx"), u16(1), u16(1), u16(1), []byte{0})
+	videoStsd := mkbox("stsd", u32(0), u32(1), mkbox("hvc1", visual, colr))
+	videoStts := mkbox("stts", u32(0), u32(1), u32(300), u32(512))
+	videoStsz := mkbox("stsz", u32(0), u32(12500), u32(300))
+	video := trak(tkhd(0, 0x10000, -0x10000, 0), "vide", 15360, 153600, videoStsd, videoStts, videoStsz)
+
+	// 双声道 44.1kHz AAC，每个样本大小不同
+	sound := bytes.Join([][]byte{
+		make([]byte, 6), u16(1), make([]byte, 8),
+		u16(2), u16(16), u16(0), u16(0), u32(44100 << 16),
+	}, nil)
+	audioStsd := mkbox("stsd", u32(0), u32(1), mkbox("mp4a", sound))
+	audioStts := mkbox("stts", u32(0), u32(1), u32(3), u32(1024))
+	audioStsz := mkbox("stsz", u32(0), u32(0), u32(3), u32(100), u32(200), u32(300))
+	audio := trak(tkhd(0x10000, 0, 0, 0x10000), "soun", 44100, 441000, audioStsd, audioStts, audioStsz)
+
+	moov := mkbox("moov", timeHeader("mvhd", 1000, 10000), video, audio)
+	file := bytes.Join([][]byte{
+		mkbox("ftyp", []byte("isom"), u32(512), []byte("isomiso2mp41")),
+		mkbox("mdat", make([]byte, 1024)),
+		moov,
+	}, nil)
+
+	info, err := Probe(bytes.NewReader(file))
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if info.Duration != 10*time.Second {
+		t.Errorf("Duration = %v, want 10s", info.Duration)
+	}
+
+	v := info.Video
+	if v == nil {
+		t.Fatal("Video = nil")
+	}
+	if v.Codec != "HEVC" || v.Width != 1920 || v.Height != 1080 || v.Rotation != 90 {
+		t.Errorf("Video = %+v", v)
+	}
+	if w, h := v.DisplaySize(); w != 1080 || h != 1920 {
+		t.Errorf("DisplaySize() = %dx%d, want 1080x1920", w, h)
+	}
+	if v.FrameRate != 30 {
+		t.Errorf("FrameRate = %v, want 30", v.FrameRate)
+	}
+	if v.Bitrate != 3000000 {
+		t.Errorf("Bitrate = %d, want 3000000", v.Bitrate)
+	}
+	if v.ColourPrimaries != "BT.709" || v.TransferCharacteristics != "BT.709" || v.MatrixCoefficients != "BT.709" {
+		t.Errorf("colour = %q %q %q", v.ColourPrimaries, v.TransferCharacteristics, v.MatrixCoefficients)
+	}
+
+	a := info.Audio
+	if a == nil {
+		t.Fatal("Audio = nil")
+	}
+	if a.Codec != "AAC" || a.Channels != 2 || a.SampleRate != 44100 || a.Duration != 10*time.Second {
+		t.Errorf("Audio = %+v", a)
+	}
+	if a.Bitrate != 480 {
+		t.Errorf("Audio.Bitrate = %d, want 480", a.Bitrate)
+	}
+}
+
+func TestProbeNoMoov(t *testing.T) {
+	file := mkbox("ftyp", []byte("isom"), u32(512))
+	if _, err := Probe(bytes.NewReader(file)); err != ErrNoMoov {
+		t.Errorf("Probe() error = %v, want ErrNoMoov", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Package pb 提供不依赖 .proto 生成代码的 protobuf 编解码辅助
+// 平台接口使用的 protobuf 消息字段较少且经常变动，按字段号手工读写比维护生成代码更简单
+package pb
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field 一个已解析的字段
+type Field struct {
+	Num    protowire.Number
+	Type   protowire.Type
+	Varint uint64
+	Bytes  []byte
+}
+
+// String 以字符串读取 bytes 类型字段
+func (f Field) String() string {
+	return string(f.Bytes)
+}
+
+// Bool 以布尔值读取 varint 类型字段
+func (f Field) Bool() bool {
+	return f.Varint != 0
+}
+
+// Int64 以有符号整数读取 varint 类型字段
+func (f Field) Int64() int64 {
+	return int64(f.Varint)
+}
+
+// Walk 依次遍历 b 中的每个字段，fixed32/fixed64 类型的值存放在 Varint 中
+func Walk(b []byte, fn func(f Field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("pb: invalid tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		f := Field{Num: num, Type: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.Varint, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(b)
+			f.Varint = uint64(v)
+		case protowire.Fixed64Type:
+			f.Varint, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			f.Bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("pb: invalid field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendVarint 追加一个 varint 字段，值为 0 时省略
+func AppendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// AppendBytes 追加一个 bytes 字段，值为空时省略
+func AppendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// AppendString 追加一个 string 字段，值为空时省略
+func AppendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
@@ -0,0 +1,179 @@
+package douyin
+
+import (
+	"context"
+)
+
+// FollowStatus 与对方的关注关系
+type FollowStatus int
+
+const (
+	// FollowNone 未关注
+	FollowNone FollowStatus = 0
+	// FollowFollowing 已关注
+	FollowFollowing FollowStatus = 1
+	// FollowMutual 互相关注
+	FollowMutual FollowStatus = 2
+	// FollowRequested 已申请关注 (对方为私密账号)
+	FollowRequested FollowStatus = 4
+)
+
+// DiggResult 点赞/取消点赞的结果
+type DiggResult struct {
+	AwemeID string
+	Liked   bool
+}
+
+// CollectResult 收藏/取消收藏的结果
+type CollectResult struct {
+	AwemeID   string
+	Collected bool
+}
+
+// FollowResult 关注/取消关注的结果
+type FollowResult struct {
+	SecUserID string
+	Status    FollowStatus
+}
+
+// 重复操作时接口返回的状态码，仅这些状态码按已处于目标状态处理
+// 状态码与评论等接口共用同一取值空间，新增时不能与 commentErrorCodes、statusPrivateAccount 重复
+const (
+	// statusAlreadyLiked 重复点赞
+	statusAlreadyLiked = 2065
+	// statusAlreadyCollected 重复收藏
+	statusAlreadyCollected = 2068
+)
+
+// alreadyDone 判断响应是否成功或表示已处于目标状态，此类响应按成功处理
+// 只比较状态码，"作品已经被删除"、"操作重复，请稍后再试" 等其它失败仍返回错误
+func alreadyDone(action string, r *BaseResponse) bool {
+	if r.StatusCode == 0 {
+		return true
+	}
+	switch action {
+	case "like":
+		return r.StatusCode == statusAlreadyLiked
+	case "collect":
+		return r.StatusCode == statusAlreadyCollected
+	default:
+		return false
+	}
+}
+
+// Like 点赞作品，已点赞时不返回错误
+func (c *DyClient) Like(ctx context.Context, awemeID string) (*DiggResult, error) {
+	return c.digg(ctx, awemeID, true)
+}
+
+// Unlike 取消点赞
+func (c *DyClient) Unlike(ctx context.Context, awemeID string) (*DiggResult, error) {
+	return c.digg(ctx, awemeID, false)
+}
+
+func (c *DyClient) digg(ctx context.Context, awemeID string, like bool) (*DiggResult, error) {
+	action, typ := "like", "1"
+	if !like {
+		action, typ = "unlike", "0"
+	}
+	if err := c.postAction(ctx, action, "/aweme/v1/web/commit/item/digg/", "/video/"+awemeID, map[string]string{
+		"aweme_id":  awemeID,
+		"item_type": "0",
+		"type":      typ,
+	}, nil); err != nil {
+		return nil, err
+	}
+	return &DiggResult{AwemeID: awemeID, Liked: like}, nil
+}
+
+// Collect 收藏作品，已收藏时不返回错误
+func (c *DyClient) Collect(ctx context.Context, awemeID string) (*CollectResult, error) {
+	return c.collect(ctx, awemeID, true)
+}
+
+// Uncollect 取消收藏
+func (c *DyClient) Uncollect(ctx context.Context, awemeID string) (*CollectResult, error) {
+	return c.collect(ctx, awemeID, false)
+}
+
+func (c *DyClient) collect(ctx context.Context, awemeID string, collect bool) (*CollectResult, error) {
+	action, typ := "collect", "1"
+	if !collect {
+		action, typ = "uncollect", "0"
+	}
+	if err := c.postAction(ctx, action, "/aweme/v1/web/aweme/collect/", "/video/"+awemeID, map[string]string{
+		"action":     typ,
+		"aweme_id":   awemeID,
+		"aweme_type": "0",
+	}, nil); err != nil {
+		return nil, err
+	}
+	return &CollectResult{AwemeID: awemeID, Collected: collect}, nil
+}
+
+// Follow 关注用户
+// 对方为私密账号时 Status 为 FollowRequested
+func (c *DyClient) Follow(ctx context.Context, secUserID string) (*FollowResult, error) {
+	return c.follow(ctx, secUserID, true)
+}
+
+// Unfollow 取消关注
+func (c *DyClient) Unfollow(ctx context.Context, secUserID string) (*FollowResult, error) {
+	return c.follow(ctx, secUserID, false)
+}
+
+func (c *DyClient) follow(ctx context.Context, secUserID string, follow bool) (*FollowResult, error) {
+	action, typ := "follow", "1"
+	status := FollowFollowing
+	if !follow {
+		action, typ = "unfollow", "0"
+		status = FollowNone
+	}
+
+	var result struct {
+		BaseResponse
+		FollowStatus *FollowStatus `json:"follow_status"`
+	}
+	if err := c.postAction(ctx, action, "/aweme/v1/web/commit/follow/user/", "/user/"+secUserID, map[string]string{
+		"type":        typ,
+		"sec_user_id": secUserID,
+		"channel_id":  "3",
+		"from":        "18",
+	}, &result); err != nil {
+		return nil, err
+	}
+	if result.FollowStatus != nil {
+		status = *result.FollowStatus
+	}
+	return &FollowResult{SecUserID: secUserID, Status: status}, nil
+}
+
+// resultResponse 由 postAction 的 result 参数实现，用于读取通用的状态字段
+type resultResponse interface {
+	base() *BaseResponse
+}
+
+func (r *BaseResponse) base() *BaseResponse {
+	return r
+}
+
+// postAction 发送互动类请求，result 为空时只解析通用状态字段
+func (c *DyClient) postAction(ctx context.Context, action, path, refererPath string, form map[string]string, result resultResponse) error {
+	if result == nil {
+		result = &BaseResponse{}
+	}
+	_, err := c.client.R().
+		SetContext(ctx).
+		SetResult(result).
+		SetHeader("Referer", douyinWebURL+refererPath).
+		SetQueryParams(c.webQueryParams(nil)).
+		SetFormData(form).
+		Post(douyinWebURL + path)
+	if err != nil {
+		return err
+	}
+	if r := result.base(); !alreadyDone(action, r) {
+		return r.check(action)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package douyin
+
+import "testing"
+
+func TestAlreadyDone(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		resp   BaseResponse
+		want   bool
+	}{
+		{"success", "like", BaseResponse{StatusCode: 0}, true},
+		{"already liked", "like", BaseResponse{StatusCode: statusAlreadyLiked, StatusMsg: "已赞"}, true},
+		{"already collected", "collect", BaseResponse{StatusCode: statusAlreadyCollected}, true},
+		{"code belongs to another action", "collect", BaseResponse{StatusCode: statusAlreadyLiked}, false},
+		{"follow has no idempotent code", "follow", BaseResponse{StatusCode: statusAlreadyLiked}, false},
+		{"unlike has no idempotent code", "unlike", BaseResponse{StatusCode: statusAlreadyLiked}, false},
+		{"blocked", "follow", BaseResponse{StatusCode: 2060, StatusMsg: "已经被拉黑"}, false},
+		{"rate limited", "collect", BaseResponse{StatusCode: 2150, StatusMsg: "操作重复，请稍后再试"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alreadyDone(tt.action, &tt.resp); got != tt.want {
+				t.Errorf("alreadyDone(%q, %+v) = %v, want %v", tt.action, tt.resp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlreadyDoneCodesAreUnique(t *testing.T) {
+	// 其它接口已赋予含义的状态码不能被当作重复操作
+	codes := []int{statusPrivateAccount}
+	for code := range commentErrorCodes {
+		codes = append(codes, code)
+	}
+	for _, code := range codes {
+		for _, action := range []string{"like", "unlike", "collect", "uncollect", "follow", "unfollow"} {
+			if alreadyDone(action, &BaseResponse{StatusCode: code}) {
+				t.Errorf("status code %d is treated as %s already done", code, action)
+			}
+		}
+	}
+}
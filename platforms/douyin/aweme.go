@@ -0,0 +1,91 @@
+package douyin
+
+// URLInfo 抖音接口中通用的资源地址结构
+type URLInfo struct {
+	URI      string   `json:"uri"`
+	URLList  []string `json:"url_list"`
+	Width    int      `json:"width"`
+	Height   int      `json:"height"`
+	DataSize int64    `json:"data_size"`
+	FileHash string   `json:"file_hash"`
+}
+
+// BitRate 视频的一个清晰度档位
+type BitRate struct {
+	GearName    string  `json:"gear_name"`
+	QualityType int     `json:"quality_type"`
+	BitRate     int     `json:"bit_rate"`
+	IsH265      int     `json:"is_h265"`
+	Format      string  `json:"format"`
+	PlayAddr    URLInfo `json:"play_addr"`
+}
+
+// Video 作品的视频信息
+type Video struct {
+	PlayAddr     URLInfo   `json:"play_addr"`
+	DownloadAddr URLInfo   `json:"download_addr"`
+	Cover        URLInfo   `json:"cover"`
+	OriginCover  URLInfo   `json:"origin_cover"`
+	Duration     int       `json:"duration"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Ratio        string    `json:"ratio"`
+	BitRate      []BitRate `json:"bit_rate"`
+}
+
+// AwemeImage 图文作品中的一张图片
+type AwemeImage struct {
+	URI             string   `json:"uri"`
+	URLList         []string `json:"url_list"`
+	DownloadURLList []string `json:"download_url_list"`
+	Width           int      `json:"width"`
+	Height          int      `json:"height"`
+}
+
+// Music 音乐 (原声)
+type Music struct {
+	ID         int64   `json:"id"`
+	IDStr      string  `json:"id_str"`
+	Title      string  `json:"title"`
+	Author     string  `json:"author"`
+	Album      string  `json:"album"`
+	Duration   int     `json:"duration"`
+	UserCount  int64   `json:"user_count"`
+	IsOriginal bool    `json:"is_original"`
+	PlayURL    URLInfo `json:"play_url"`
+	CoverLarge URLInfo `json:"cover_large"`
+}
+
+// AwemeStatistics 作品的互动数据
+type AwemeStatistics struct {
+	DiggCount    int64 `json:"digg_count"`
+	CommentCount int64 `json:"comment_count"`
+	ShareCount   int64 `json:"share_count"`
+	CollectCount int64 `json:"collect_count"`
+	PlayCount    int64 `json:"play_count"`
+}
+
+// Aweme 作品 (视频或图文)
+type Aweme struct {
+	AwemeID    string          `json:"aweme_id"`
+	Desc       string          `json:"desc"`
+	CreateTime int64           `json:"create_time"`
+	AwemeType  int             `json:"aweme_type"`
+	Author     UserSummary     `json:"author"`
+	Music      *Music          `json:"music"`
+	Video      Video           `json:"video"`
+	Images     []AwemeImage    `json:"images"`
+	Statistics AwemeStatistics `json:"statistics"`
+}
+
+// IsImagePost 是否为图文作品
+func (a *Aweme) IsImagePost() bool {
+	return len(a.Images) > 0
+}
+
+// AwemePage 作品列表的一页
+type AwemePage struct {
+	Awemes  []Aweme
+	Cursor  int64
+	HasMore bool
+}
@@ -0,0 +1,145 @@
+package douyin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const commentReferer = "https://creator.douyin.com/creator-micro/interactive/comment"
+
+// 评论接口的审核/风控错误，可通过 errors.Is 判断
+var (
+	// ErrCommentTooFrequent 评论过于频繁
+	ErrCommentTooFrequent = errors.New("comment too frequent")
+	// ErrCommentSensitive 评论内容包含敏感词或未通过审核
+	ErrCommentSensitive = errors.New("comment rejected by moderation")
+	// ErrCommentDuplicate 重复评论
+	ErrCommentDuplicate = errors.New("duplicate comment")
+	// ErrCommentForbidden 作者关闭了评论或账号被禁言
+	ErrCommentForbidden = errors.New("comment forbidden")
+	// ErrCommentNotFound 评论不存在或已被删除
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+// commentErrorCodes 评论接口 status_code 到错误类型的映射
+var commentErrorCodes = map[int]error{
+	2053: ErrCommentTooFrequent,
+	2054: ErrCommentDuplicate,
+	2055: ErrCommentSensitive,
+	2056: ErrCommentSensitive,
+	2096: ErrCommentForbidden,
+	2149: ErrCommentForbidden,
+	3002: ErrCommentNotFound,
+}
+
+// CommentError 评论接口返回的业务错误
+type CommentError struct {
+	Action string
+	Code   int
+	Msg    string
+	kind   error
+}
+
+func (e *CommentError) Error() string {
+	return fmt.Sprintf("%s failed, status code: %d, msg: %s", e.Action, e.Code, e.Msg)
+}
+
+// Unwrap 返回错误码对应的错误类型，未知错误码返回 nil
+func (e *CommentError) Unwrap() error {
+	return e.kind
+}
+
+// Comment 评论
+type Comment struct {
+	CID        string `json:"cid"`
+	Text       string `json:"text"`
+	AwemeID    string `json:"aweme_id"`
+	CreateTime int64  `json:"create_time"`
+	DiggCount  int64  `json:"digg_count"`
+	ReplyID    string `json:"reply_id"`
+	ReplyToID  string `json:"reply_to_reply_id"`
+	IPLabel    string `json:"ip_label"`
+	User       struct {
+		UID      string `json:"uid"`
+		SecUID   string `json:"sec_uid"`
+		Nickname string `json:"nickname"`
+	} `json:"user"`
+}
+
+type commentResponse struct {
+	BaseResponse
+	Comment Comment `json:"comment"`
+}
+
+// commentError 将非 0 的 status_code 转换为 CommentError
+func commentError(action string, r *BaseResponse) error {
+	if r.StatusCode == 0 {
+		return nil
+	}
+	return &CommentError{
+		Action: action,
+		Code:   r.StatusCode,
+		Msg:    r.StatusMsg,
+		kind:   commentErrorCodes[r.StatusCode],
+	}
+}
+
+// PublishComment 在作品下发表一级评论
+func (c *DyClient) PublishComment(ctx context.Context, awemeID, text string) (*Comment, error) {
+	return c.publishComment(ctx, "publish comment", map[string]string{
+		"aweme_id": awemeID,
+		"text":     text,
+	})
+}
+
+// ReplyComment 回复作品下的评论
+// commentID: 被回复的评论 ID，replyToID: 回复楼中楼时被回复的二级评论 ID，可为空
+func (c *DyClient) ReplyComment(ctx context.Context, awemeID, commentID, replyToID, text string) (*Comment, error) {
+	return c.publishComment(ctx, "reply comment", map[string]string{
+		"aweme_id":          awemeID,
+		"text":              text,
+		"reply_id":          commentID,
+		"reply_to_reply_id": replyToID,
+	})
+}
+
+func (c *DyClient) publishComment(ctx context.Context, action string, form map[string]string) (*Comment, error) {
+	form["text_extra"] = "[]"
+	form["paste_edit_method"] = "non_paste"
+	form["one_level_comment_rank"] = "-1"
+
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&commentResponse{}).
+		SetHeader("Referer", commentReferer).
+		SetQueryParams(creatorQueryParams(nil)).
+		SetFormData(form).
+		Post(fmt.Sprintf("%s/aweme/janus/creator/comment/aweme/v1/comment/publish/", douyinCreatorURL))
+	if err != nil {
+		return nil, err
+	}
+
+	result := resp.Result().(*commentResponse)
+	if err := commentError(action, &result.BaseResponse); err != nil {
+		return nil, err
+	}
+	return &result.Comment, nil
+}
+
+// DeleteComment 删除评论
+func (c *DyClient) DeleteComment(ctx context.Context, commentID string) error {
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&BaseResponse{}).
+		SetHeader("Referer", commentReferer).
+		SetQueryParams(creatorQueryParams(nil)).
+		SetFormData(map[string]string{
+			"cid": commentID,
+		}).
+		Post(fmt.Sprintf("%s/aweme/janus/creator/comment/aweme/v1/comment/delete/", douyinCreatorURL))
+	if err != nil {
+		return err
+	}
+	return commentError("delete comment", resp.Result().(*BaseResponse))
+}
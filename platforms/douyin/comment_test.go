@@ -0,0 +1,68 @@
+package douyin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+var commentSentinels = []error{ErrCommentTooFrequent, ErrCommentSensitive, ErrCommentDuplicate, ErrCommentForbidden, ErrCommentNotFound}
+
+func TestCommentError(t *testing.T) {
+	tests := []struct {
+		code int
+		want error
+	}{
+		{0, nil},
+		{2053, ErrCommentTooFrequent},
+		{2054, ErrCommentDuplicate},
+		{2055, ErrCommentSensitive},
+		{2056, ErrCommentSensitive},
+		{2096, ErrCommentForbidden},
+		{2149, ErrCommentForbidden},
+		{3002, ErrCommentNotFound},
+		{8, nil}, // 未知错误码仍返回 CommentError，但不对应任何错误类型
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.code), func(t *testing.T) {
+			err := commentError("publish comment", &BaseResponse{StatusCode: tt.code, StatusMsg: "msg"})
+			if tt.code == 0 {
+				if err != nil {
+					t.Fatalf("commentError() = %v, want nil", err)
+				}
+				return
+			}
+			var ce *CommentError
+			if !errors.As(err, &ce) || ce.Code != tt.code || ce.Action != "publish comment" {
+				t.Fatalf("commentError() = %#v", err)
+			}
+			for _, sentinel := range commentSentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, sentinel, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPublishCommentError(t *testing.T) {
+	c := stubClient(func(r *http.Request) string {
+		return `{"status_code":2055,"status_msg":"评论内容违规"}`
+	})
+	_, err := c.ReplyComment(context.Background(), "1", "c1", "", "hi")
+	if !errors.Is(err, ErrCommentSensitive) || err.Error() != "reply comment failed, status code: 2055, msg: 评论内容违规" {
+		t.Errorf("ReplyComment() error = %v", err)
+	}
+
+	c = stubClient(func(r *http.Request) string {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("cid") != "c1" {
+			t.Errorf("delete form = %v, %v", r.PostForm, err)
+		}
+		return `{"status_code":3002,"status_msg":"评论不存在"}`
+	})
+	if err := c.DeleteComment(context.Background(), "c1"); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("DeleteComment() error = %v", err)
+	}
+}
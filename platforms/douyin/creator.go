@@ -0,0 +1,230 @@
+package douyin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const creatorManageReferer = "https://creator.douyin.com/creator-micro/content/manage"
+
+// WorkStatus 作品在创作者中心的状态
+type WorkStatus int
+
+const (
+	// WorkStatusAll 全部作品 (仅用于筛选)
+	WorkStatusAll WorkStatus = 0
+	// WorkStatusPublished 已发布
+	WorkStatusPublished WorkStatus = 1
+	// WorkStatusReviewing 审核中
+	WorkStatusReviewing WorkStatus = 2
+	// WorkStatusRejected 审核未通过
+	WorkStatusRejected WorkStatus = 3
+)
+
+func (s WorkStatus) String() string {
+	switch s {
+	case WorkStatusPublished:
+		return "published"
+	case WorkStatusReviewing:
+		return "reviewing"
+	case WorkStatusRejected:
+		return "rejected"
+	default:
+		return "all"
+	}
+}
+
+// Work 创作者中心的作品
+type Work struct {
+	AwemeID    string `json:"aweme_id"`
+	Desc       string `json:"desc"`
+	CreateTime int64  `json:"create_time"`
+	MediaType  int    `json:"media_type"`
+	Status     struct {
+		IsDelete      bool `json:"is_delete"`
+		IsPrivate     bool `json:"is_private"`
+		InReviewing   bool `json:"in_reviewing"`
+		IsProhibited  bool `json:"is_prohibited"`
+		PrivateStatus int  `json:"private_status"`
+		ReviewResult  struct {
+			ReviewStatus int `json:"review_status"`
+		} `json:"review_result"`
+	} `json:"status"`
+	Statistics struct {
+		PlayCount    int64 `json:"play_count"`
+		DiggCount    int64 `json:"digg_count"`
+		CommentCount int64 `json:"comment_count"`
+		ShareCount   int64 `json:"share_count"`
+		CollectCount int64 `json:"collect_count"`
+	} `json:"statistics"`
+	Video struct {
+		Cover struct {
+			URLList []string `json:"url_list"`
+		} `json:"cover"`
+		Duration int `json:"duration"`
+	} `json:"video"`
+	IsTop int `json:"is_top"`
+}
+
+// WorkStatus 根据审核信息推导作品状态
+func (w *Work) WorkStatus() WorkStatus {
+	switch {
+	case w.Status.InReviewing:
+		return WorkStatusReviewing
+	case w.Status.IsProhibited || w.Status.ReviewResult.ReviewStatus == 2:
+		return WorkStatusRejected
+	default:
+		return WorkStatusPublished
+	}
+}
+
+// Visibility 返回作品当前的可见范围
+func (w *Work) Visibility() Visibility {
+	return Visibility(w.Status.PrivateStatus)
+}
+
+// WorkList 作品列表的一页
+type WorkList struct {
+	BaseResponse
+	AwemeList []Work `json:"aweme_list"`
+	HasMore   bool   `json:"has_more"`
+	MaxCursor int64  `json:"max_cursor"`
+	Total     int    `json:"total"`
+}
+
+// WorkUpdate 编辑作品时需要修改的字段，nil 表示保持不变
+type WorkUpdate struct {
+	// Text 新的作品文案，会整体替换原文案及其中的话题与 @，需要保留的话题与用户应通过 Hashtags、Mentions 重新指定
+	Text *string
+	// Hashtags 话题名称 (不带 #)，与 PublishOptions.Hashtags 相同，追加到 Text 末尾，仅在 Text 非 nil 时生效
+	Hashtags []string
+	// Mentions 需要 @ 的抖音号或昵称，与 PublishOptions.Mentions 相同，仅在 Text 非 nil 时生效
+	Mentions   []string
+	Visibility *Visibility
+}
+
+// creatorQueryParams 创作者中心接口通用的查询参数
+func creatorQueryParams(extra map[string]string) map[string]string {
+	params := map[string]string{
+		"cookie_enabled":   "true",
+		"screen_width":     "2048",
+		"screen_height":    "1152",
+		"browser_language": "zh-CN",
+		"browser_platform": "Win32",
+		"browser_name":     "Mozilla",
+		"browser_version":  "5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36 Edg/139.0.0.0",
+		"browser_online":   "true",
+		"timezone_name":    "Asia/Shanghai",
+		"aid":              "1128",
+		"support_h265":     "1",
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
+	return params
+}
+
+// ListWorks 获取自己发布的作品列表
+// status: 按状态筛选，WorkStatusAll 表示全部
+// cursor: 上一页返回的 MaxCursor，首页传 0
+func (c *DyClient) ListWorks(ctx context.Context, status WorkStatus, cursor int64, count int) (*WorkList, error) {
+	if count <= 0 {
+		count = 12
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&WorkList{}).
+		SetHeader("Referer", creatorManageReferer).
+		SetQueryParams(creatorQueryParams(map[string]string{
+			"status":     strconv.Itoa(int(status)),
+			"count":      strconv.Itoa(count),
+			"max_cursor": strconv.FormatInt(cursor, 10),
+			"scene":      "star_atlas",
+		})).
+		Get(fmt.Sprintf("%s/janus/douyin/creator/pc/work_list", douyinCreatorURL))
+	if err != nil {
+		return nil, err
+	}
+
+	list := resp.Result().(*WorkList)
+	if err := list.check("list works"); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// EditWork 修改作品的文案或可见范围
+func (c *DyClient) EditWork(ctx context.Context, awemeID string, update WorkUpdate) error {
+	body := map[string]interface{}{
+		"item_id": awemeID,
+	}
+	if update.Text != nil {
+		tags, users, err := c.resolveTextEntities(ctx, update.Hashtags, update.Mentions)
+		if err != nil {
+			return err
+		}
+		text, textExtras := composeText("", *update.Text, tags, users)
+		textExtraJSON, err := json.Marshal(textExtras)
+		if err != nil {
+			return err
+		}
+		body["text"] = text
+		body["text_extra"] = string(textExtraJSON)
+	}
+	if update.Visibility != nil {
+		body["visibility_type"] = int(*update.Visibility)
+	}
+	return c.postCreatorAction(ctx, "edit work", "/web/api/media/aweme/update/", nil, body)
+}
+
+// SetWorkVisibility 修改作品的可见范围
+func (c *DyClient) SetWorkVisibility(ctx context.Context, awemeID string, visibility Visibility) error {
+	return c.EditWork(ctx, awemeID, WorkUpdate{Visibility: &visibility})
+}
+
+// DeleteWork 删除作品
+func (c *DyClient) DeleteWork(ctx context.Context, awemeID string) error {
+	return c.postCreatorAction(ctx, "delete work", "/web/api/media/aweme/delete/", map[string]string{
+		"aweme_id": awemeID,
+	}, nil)
+}
+
+// PinWork 置顶作品
+func (c *DyClient) PinWork(ctx context.Context, awemeID string) error {
+	return c.setWorkPinned(ctx, awemeID, true)
+}
+
+// UnpinWork 取消置顶作品
+func (c *DyClient) UnpinWork(ctx context.Context, awemeID string) error {
+	return c.setWorkPinned(ctx, awemeID, false)
+}
+
+func (c *DyClient) setWorkPinned(ctx context.Context, awemeID string, pinned bool) error {
+	action, stick := "unpin work", "0"
+	if pinned {
+		action, stick = "pin work", "1"
+	}
+	return c.postCreatorAction(ctx, action, "/web/api/media/aweme/stick/", map[string]string{
+		"aweme_id": awemeID,
+		"stick":    stick,
+	}, nil)
+}
+
+// postCreatorAction 向创作者中心发送一个修改类请求并检查响应状态
+func (c *DyClient) postCreatorAction(ctx context.Context, action, path string, query map[string]string, body interface{}) error {
+	req := c.client.R().
+		SetContext(ctx).
+		SetResult(&BaseResponse{}).
+		SetHeader("Referer", creatorManageReferer).
+		SetQueryParams(creatorQueryParams(query))
+	if body != nil {
+		req.SetHeader("Content-Type", "application/json").SetBody(body)
+	}
+	resp, err := req.Post(douyinCreatorURL + path)
+	if err != nil {
+		return err
+	}
+	return resp.Result().(*BaseResponse).check(action)
+}
@@ -0,0 +1,224 @@
+package douyin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const creatorDataReferer = "https://creator.douyin.com/creator-micro/data-center/operation"
+
+// DataPoint 时间序列中的一个数据点
+type DataPoint struct {
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+}
+
+// dataLocation 数据中心的日期按北京时间统计，解析时固定使用该时区，与运行环境的时区无关
+var dataLocation = time.FixedZone("CST", 8*60*60)
+
+// UnmarshalJSON 兼容 "20060102"、"2006-01-02" 与 RFC3339 三种日期格式，数值可能以字符串返回
+// RFC3339 为 time.Time 默认的序列化格式，保证序列化后的 DataPoint 可以重新解析
+func (p *DataPoint) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Date  string      `json:"date"`
+		Value json.Number `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var (
+		date time.Time
+		err  error
+	)
+	switch len(raw.Date) {
+	case len("20060102"):
+		date, err = time.ParseInLocation("20060102", raw.Date, dataLocation)
+	case len("2006-01-02"):
+		date, err = time.ParseInLocation("2006-01-02", raw.Date, dataLocation)
+	default:
+		date, err = time.Parse(time.RFC3339, raw.Date)
+	}
+	if err != nil {
+		return fmt.Errorf("parse data point date %q failed: %w", raw.Date, err)
+	}
+	p.Date = date
+	p.Value = 0
+	if raw.Value != "" {
+		p.Value, err = raw.Value.Float64()
+		if err != nil {
+			return fmt.Errorf("parse data point value %q failed: %w", raw.Value, err)
+		}
+	}
+	return nil
+}
+
+// TimeSeries 某个指标按天的时间序列
+type TimeSeries struct {
+	Metric string      `json:"metric"`
+	Points []DataPoint `json:"points"`
+}
+
+// MetricRecord 扁平化的一条指标记录，便于直接写入表格或数据库
+type MetricRecord struct {
+	Date   time.Time `json:"date"`
+	Metric string    `json:"metric"`
+	Value  float64   `json:"value"`
+}
+
+// Records 将时间序列展开为逐日记录
+func (s TimeSeries) Records() []MetricRecord {
+	records := make([]MetricRecord, 0, len(s.Points))
+	for _, p := range s.Points {
+		records = append(records, MetricRecord{Date: p.Date, Metric: s.Metric, Value: p.Value})
+	}
+	return records
+}
+
+// AccountOverview 账号在一段时间内的总览数据
+type AccountOverview struct {
+	Followers TimeSeries `json:"followers"`
+	NewFans   TimeSeries `json:"new_fans"`
+	Plays     TimeSeries `json:"plays"`
+	Likes     TimeSeries `json:"likes"`
+	Comments  TimeSeries `json:"comments"`
+	Shares    TimeSeries `json:"shares"`
+}
+
+// Records 将所有指标展开为逐日记录
+func (o *AccountOverview) Records() []MetricRecord {
+	var records []MetricRecord
+	for _, s := range []TimeSeries{o.Followers, o.NewFans, o.Plays, o.Likes, o.Comments, o.Shares} {
+		records = append(records, s.Records()...)
+	}
+	return records
+}
+
+// TrafficSource 流量来源占比
+type TrafficSource struct {
+	Source  string  `json:"source"`
+	Percent float64 `json:"percent"`
+}
+
+// WorkMetrics 单个作品的数据
+type WorkMetrics struct {
+	AwemeID          string          `json:"aweme_id"`
+	PlayCount        int64           `json:"play_count"`
+	LikeCount        int64           `json:"digg_count"`
+	CommentCount     int64           `json:"comment_count"`
+	ShareCount       int64           `json:"share_count"`
+	CompletionRate   float64         `json:"completion_rate"`
+	AvgWatchDuration float64         `json:"avg_play_duration"`
+	TrafficSources   []TrafficSource `json:"traffic_source"`
+	Plays            TimeSeries      `json:"plays"`
+}
+
+// Distribution 画像中某一维度的分布项
+type Distribution struct {
+	Key     string  `json:"key"`
+	Percent float64 `json:"value"`
+}
+
+// FanPortrait 粉丝画像
+type FanPortrait struct {
+	Gender   []Distribution `json:"gender"`
+	Age      []Distribution `json:"age"`
+	Province []Distribution `json:"province"`
+	City     []Distribution `json:"city"`
+	Device   []Distribution `json:"device"`
+	Interest []Distribution `json:"interest"`
+}
+
+// GetAccountOverview 获取账号在 [start, end] 日期范围内的粉丝、播放、点赞等趋势
+func (c *DyClient) GetAccountOverview(ctx context.Context, start, end time.Time) (*AccountOverview, error) {
+	var result struct {
+		BaseResponse
+		Trends map[string][]DataPoint `json:"trends"`
+	}
+	_, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", creatorDataReferer).
+		SetQueryParams(creatorQueryParams(map[string]string{
+			"start_date": start.Format("20060102"),
+			"end_date":   end.Format("20060102"),
+			"metrics":    "total_fans,new_fans,play,digg,comment,share",
+		})).
+		Get(fmt.Sprintf("%s/janus/douyin/creator/data/overview/trend", douyinCreatorURL))
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get account overview"); err != nil {
+		return nil, err
+	}
+
+	series := func(metric string) TimeSeries {
+		return TimeSeries{Metric: metric, Points: result.Trends[metric]}
+	}
+	return &AccountOverview{
+		Followers: series("total_fans"),
+		NewFans:   series("new_fans"),
+		Plays:     series("play"),
+		Likes:     series("digg"),
+		Comments:  series("comment"),
+		Shares:    series("share"),
+	}, nil
+}
+
+// GetWorkMetrics 获取单个作品的播放、完播率、平均观看时长、流量来源等数据
+// days: 播放趋势统计的天数
+func (c *DyClient) GetWorkMetrics(ctx context.Context, awemeID string, days int) (*WorkMetrics, error) {
+	if days <= 0 {
+		days = 7
+	}
+	var result struct {
+		BaseResponse
+		Data struct {
+			WorkMetrics
+			PlayTrend []DataPoint `json:"play_trend"`
+		} `json:"data"`
+	}
+	_, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", creatorDataReferer).
+		SetQueryParams(creatorQueryParams(map[string]string{
+			"item_id": awemeID,
+			"days":    strconv.Itoa(days),
+		})).
+		Get(fmt.Sprintf("%s/janus/douyin/creator/data/item_analysis/detail", douyinCreatorURL))
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get work metrics"); err != nil {
+		return nil, err
+	}
+
+	metrics := result.Data.WorkMetrics
+	metrics.AwemeID = awemeID
+	metrics.Plays = TimeSeries{Metric: "play", Points: result.Data.PlayTrend}
+	return &metrics, nil
+}
+
+// GetFanPortrait 获取粉丝画像 (性别、年龄、地域、设备、兴趣分布)
+func (c *DyClient) GetFanPortrait(ctx context.Context) (*FanPortrait, error) {
+	var result struct {
+		BaseResponse
+		Data FanPortrait `json:"data"`
+	}
+	_, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", creatorDataReferer).
+		SetQueryParams(creatorQueryParams(nil)).
+		Get(fmt.Sprintf("%s/janus/douyin/creator/data/fans/portrait", douyinCreatorURL))
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get fan portrait"); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
@@ -0,0 +1,74 @@
+package douyin
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDataPointUnmarshalJSON(t *testing.T) {
+	want := time.Date(2024, 3, 1, 0, 0, 0, 0, dataLocation)
+	tests := []struct {
+		name  string
+		in    string
+		value float64
+	}{
+		{"compact date", `{"date":"20240301","value":12}`, 12},
+		{"dashed date", `{"date":"2024-03-01","value":12.5}`, 12.5},
+		{"string value", `{"date":"20240301","value":"34"}`, 34},
+		{"rfc3339", `{"date":"2024-03-01T00:00:00+08:00","value":1}`, 1},
+		{"missing value", `{"date":"2024-03-01"}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p DataPoint
+			if err := json.Unmarshal([]byte(tt.in), &p); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !p.Date.Equal(want) {
+				t.Errorf("Date = %v, want %v", p.Date, want)
+			}
+			if p.Value != tt.value {
+				t.Errorf("Value = %v, want %v", p.Value, tt.value)
+			}
+		})
+	}
+}
+
+func TestDataPointInvalidDate(t *testing.T) {
+	var p DataPoint
+	if err := json.Unmarshal([]byte(`{"date":"03/01/2024","value":1}`), &p); err == nil {
+		t.Error("Unmarshal() expected error")
+	}
+}
+
+func TestDataPointRoundTrip(t *testing.T) {
+	var series TimeSeries
+	if err := json.Unmarshal([]byte(`{"metric":"play","points":[{"date":"20240301","value":"7"}]}`), &series); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got TimeSeries
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", data, err)
+	}
+	if len(got.Points) != 1 || !got.Points[0].Date.Equal(series.Points[0].Date) || got.Points[0].Value != 7 {
+		t.Errorf("round trip = %+v, want %+v", got, series)
+	}
+
+	records := series.Records()
+	data, err = json.Marshal(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotRecords []MetricRecord
+	if err := json.Unmarshal(data, &gotRecords); err != nil {
+		t.Fatal(err)
+	}
+	if len(gotRecords) != 1 || !gotRecords[0].Date.Equal(records[0].Date) || gotRecords[0].Metric != "play" {
+		t.Errorf("records round trip = %+v, want %+v", gotRecords, records)
+	}
+}
@@ -0,0 +1,202 @@
+package douyin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// jsonResponse 构造 roundTripFunc 返回的 JSON 响应
+func jsonResponse(r *http.Request, body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}
+}
+
+// stubClient 返回一个不访问网络的客户端，所有请求交给 handle 处理
+func stubClient(handle func(r *http.Request) string) *DyClient {
+	c := New("msToken=test", nil)
+	c.client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return jsonResponse(r, handle(r)), nil
+	}))
+	return c
+}
+
+func TestWorkStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want WorkStatus
+	}{
+		{"published", `{}`, WorkStatusPublished},
+		{"reviewing", `{"in_reviewing":true}`, WorkStatusReviewing},
+		{"prohibited", `{"is_prohibited":true}`, WorkStatusRejected},
+		{"review failed", `{"review_result":{"review_status":2}}`, WorkStatusRejected},
+		{"reviewing wins over prohibited", `{"in_reviewing":true,"is_prohibited":true}`, WorkStatusReviewing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w Work
+			if err := json.Unmarshal([]byte(`{"status":`+tt.in+`}`), &w); err != nil {
+				t.Fatal(err)
+			}
+			if got := w.WorkStatus(); got != tt.want {
+				t.Errorf("WorkStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListWorks(t *testing.T) {
+	var query map[string][]string
+	c := stubClient(func(r *http.Request) string {
+		query = r.URL.Query()
+		return `{"status_code":0,"has_more":true,"max_cursor":1700000000000,"aweme_list":[{"aweme_id":"1","status":{"in_reviewing":true}}]}`
+	})
+	list, err := c.ListWorks(context.Background(), WorkStatusReviewing, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.AwemeList) != 1 || list.AwemeList[0].WorkStatus() != WorkStatusReviewing || !list.HasMore || list.MaxCursor != 1700000000000 {
+		t.Errorf("ListWorks() = %+v", list)
+	}
+	if query["status"][0] != "2" || query["count"][0] != "12" || query["max_cursor"][0] != "0" {
+		t.Errorf("query = %v", query)
+	}
+
+	c = stubClient(func(r *http.Request) string {
+		return `{"status_code":8,"status_msg":"未登录"}`
+	})
+	if _, err := c.ListWorks(context.Background(), WorkStatusAll, 0, 20); err == nil || !strings.Contains(err.Error(), "list works") {
+		t.Errorf("ListWorks() error = %v, want list works failure", err)
+	}
+}
+
+func TestCreatorActions(t *testing.T) {
+	text := "新的文案"
+	private := VisibilityPrivate
+	tests := []struct {
+		name   string
+		call   func(c *DyClient) error
+		path   string
+		query  map[string]string
+		body   map[string]interface{}
+		action string
+	}{
+		{
+			name: "edit text keeps hashtags and mentions",
+			call: func(c *DyClient) error {
+				return c.EditWork(context.Background(), "1", WorkUpdate{Text: &text, Hashtags: []string{"美食"}, Mentions: []string{"alice"}})
+			},
+			path:   "/web/api/media/aweme/update/",
+			body:   map[string]interface{}{"item_id": "1", "text": "新的文案 #美食 @Alice"},
+			action: "edit work",
+		},
+		{
+			name:   "edit visibility only",
+			call:   func(c *DyClient) error { return c.SetWorkVisibility(context.Background(), "1", private) },
+			path:   "/web/api/media/aweme/update/",
+			body:   map[string]interface{}{"item_id": "1", "visibility_type": float64(private)},
+			action: "edit work",
+		},
+		{
+			name:   "delete",
+			call:   func(c *DyClient) error { return c.DeleteWork(context.Background(), "1") },
+			path:   "/web/api/media/aweme/delete/",
+			query:  map[string]string{"aweme_id": "1"},
+			action: "delete work",
+		},
+		{
+			name:   "pin",
+			call:   func(c *DyClient) error { return c.PinWork(context.Background(), "1") },
+			path:   "/web/api/media/aweme/stick/",
+			query:  map[string]string{"aweme_id": "1", "stick": "1"},
+			action: "pin work",
+		},
+		{
+			name:   "unpin",
+			call:   func(c *DyClient) error { return c.UnpinWork(context.Background(), "1") },
+			path:   "/web/api/media/aweme/stick/",
+			query:  map[string]string{"aweme_id": "1", "stick": "0"},
+			action: "unpin work",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				req  *http.Request
+				body map[string]interface{}
+				fail bool
+			)
+			handle := func(r *http.Request) string {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/search/challengesug/"):
+					return `{"status_code":0,"sug_list":[{"cid":"123","cha_name":"美食"}]}`
+				case strings.HasSuffix(r.URL.Path, "/discover/search/"):
+					return `{"status_code":0,"user_list":[{"user_info":{"uid":"u1","sec_uid":"s1","nickname":"Alice","unique_id":"alice"}}]}`
+				}
+				req = r
+				if r.Body != nil {
+					json.NewDecoder(r.Body).Decode(&body)
+				}
+				if fail {
+					return `{"status_code":4,"status_msg":"作品不存在"}`
+				}
+				return `{"status_code":0}`
+			}
+
+			if err := tt.call(stubClient(handle)); err != nil {
+				t.Fatal(err)
+			}
+			if req.URL.Path != tt.path {
+				t.Errorf("path = %s, want %s", req.URL.Path, tt.path)
+			}
+			for k, v := range tt.query {
+				if got := req.URL.Query().Get(k); got != v {
+					t.Errorf("query %s = %q, want %q", k, got, v)
+				}
+			}
+			for k, v := range tt.body {
+				if body[k] != v {
+					t.Errorf("body %s = %v, want %v", k, body[k], v)
+				}
+			}
+			if tt.body != nil && body["text"] != nil {
+				var extras []TextExtra
+				if err := json.Unmarshal([]byte(body["text_extra"].(string)), &extras); err != nil {
+					t.Fatalf("text_extra = %v: %v", body["text_extra"], err)
+				}
+				if len(extras) != 2 || extras[0].HashtagID != 123 || extras[0].Start != 5 || extras[1].UserID != "u1" || extras[1].Start != 9 {
+					t.Errorf("text_extra = %+v", extras)
+				}
+			}
+
+			fail = true
+			err := tt.call(stubClient(handle))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.action+" failed") {
+				t.Errorf("error = %v, want prefix %q", err, tt.action+" failed")
+			}
+		})
+	}
+}
+
+func TestEditWorkResolveError(t *testing.T) {
+	c := stubClient(func(r *http.Request) string {
+		return `{"status_code":0,"user_list":[]}`
+	})
+	text := "文案"
+	err := c.EditWork(context.Background(), "1", WorkUpdate{Text: &text, Mentions: []string{"nobody"}})
+	if err == nil || !strings.Contains(err.Error(), "nobody") {
+		t.Errorf("EditWork() error = %v, want unresolved mention", err)
+	}
+}
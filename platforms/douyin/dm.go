@@ -0,0 +1,412 @@
+package douyin
+
+import (
+	"context"
+	"crawler-sdk/internal/pb"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// 私信消息类型
+const (
+	// DMTypeText 文本消息
+	DMTypeText = 7
+	// DMTypeImage 图片消息
+	DMTypeImage = 27
+)
+
+// 会话类型
+const (
+	// ConversationOneToOne 单聊
+	ConversationOneToOne = 1
+	// ConversationGroup 群聊
+	ConversationGroup = 2
+)
+
+// Participant 会话成员
+type Participant struct {
+	UserID int64
+	SecUID string
+}
+
+// Conversation 私信会话
+type Conversation struct {
+	ID           string
+	ShortID      int64
+	Type         int
+	Ticket       string
+	Participants []Participant
+	Unread       int64
+	// LastMessage 会话列表中返回的最近一条消息，可能为空
+	LastMessage *DirectMessage
+}
+
+// DirectMessage 私信消息
+type DirectMessage struct {
+	ConversationID      string
+	ConversationShortID int64
+	ConversationType    int
+	ServerMessageID     int64
+	// Index 消息在会话中的序号，用于翻页与标记已读
+	Index      int64
+	Sender     int64
+	SecSender  string
+	Type       int
+	Content    string
+	CreateTime time.Time
+}
+
+// Text 返回文本消息的内容，非文本消息返回空字符串
+func (m *DirectMessage) Text() string {
+	var content struct {
+		Text string `json:"text"`
+	}
+	if json.Unmarshal([]byte(m.Content), &content) != nil {
+		return ""
+	}
+	return content.Text
+}
+
+// ConversationPage 会话列表的一页
+type ConversationPage struct {
+	Conversations []Conversation
+	Cursor        int64
+	HasMore       bool
+}
+
+// MessagePage 会话消息的一页
+type MessagePage struct {
+	Messages []DirectMessage
+	// Cursor 下一页的锚点序号，传给 ListMessages 继续获取更早的消息
+	Cursor  int64
+	HasMore bool
+}
+
+// ListConversations 获取私信会话列表，cursor 首页传 0
+func (c *DyClient) ListConversations(ctx context.Context, cursor int64) (*ConversationPage, error) {
+	var body []byte
+	body = pb.AppendVarint(body, 1, uint64(cursor))
+
+	data, err := c.imCall(ctx, "/v1/message/get_by_user_init", cmdGetMessagesByUserInit, body)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ConversationPage{}
+	var messages []DirectMessage
+	err = pb.Walk(data, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			m, err := parseMessageBody(f.Bytes)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, m)
+		case 2:
+			conv, err := parseConversation(f.Bytes)
+			if err != nil {
+				return err
+			}
+			page.Conversations = append(page.Conversations, conv)
+		case 4:
+			page.Cursor = f.Int64()
+		case 5:
+			page.HasMore = f.Bool()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode conversation list failed: %w", err)
+	}
+
+	for i := range page.Conversations {
+		conv := &page.Conversations[i]
+		for j := range messages {
+			m := &messages[j]
+			if m.ConversationID == conv.ID && (conv.LastMessage == nil || m.Index > conv.LastMessage.Index) {
+				conv.LastMessage = m
+			}
+		}
+	}
+	return page, nil
+}
+
+// ListMessages 获取会话中 anchor 序号之前的消息，按时间倒序返回
+// anchor: 首页传 0 表示从最新消息开始，之后传上一页返回的 Cursor
+func (c *DyClient) ListMessages(ctx context.Context, conv *Conversation, anchor int64, limit int) (*MessagePage, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	body := conversationRef(conv)
+	body = pb.AppendVarint(body, 4, imConversationDirectionHistory)
+	body = pb.AppendVarint(body, 5, uint64(anchor))
+	body = pb.AppendVarint(body, 6, uint64(limit))
+
+	data, err := c.imCall(ctx, "/v1/message/get_by_conversation", cmdGetMessagesByConversation, body)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MessagePage{}
+	err = pb.Walk(data, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			m, err := parseMessageBody(f.Bytes)
+			if err != nil {
+				return err
+			}
+			page.Messages = append(page.Messages, m)
+		case 2:
+			page.Cursor = f.Int64()
+		case 3:
+			page.HasMore = f.Bool()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode messages failed: %w", err)
+	}
+	return page, nil
+}
+
+// SendText 在会话中发送文本消息，返回服务端消息 ID
+func (c *DyClient) SendText(ctx context.Context, conv *Conversation, text string) (int64, error) {
+	content, err := json.Marshal(map[string]any{
+		"mention_users": []string{},
+		"aweType":       700,
+		"richTextInfos": []any{},
+		"text":          text,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return c.sendMessage(ctx, conv, DMTypeText, string(content))
+}
+
+// SendImage 上传图片并在会话中发送，返回服务端消息 ID
+func (c *DyClient) SendImage(ctx context.Context, conv *Conversation, imagePath string) (int64, error) {
+	auth, err := c.getUploadAuth(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get upload auth failed: %w", err)
+	}
+	img, err := c.uploadImage(ctx, auth, imagePath)
+	if err != nil {
+		return 0, fmt.Errorf("upload image failed: %w", err)
+	}
+	content, err := json.Marshal(map[string]any{
+		"aweType":      2702,
+		"uri":          img.ImageUri,
+		"md5":          img.ImageMd5,
+		"cover_width":  img.ImageWidth,
+		"cover_height": img.ImageHeight,
+		"file_size":    img.ImageSize,
+		"image_format": img.ImageFormat,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return c.sendMessage(ctx, conv, DMTypeImage, string(content))
+}
+
+func (c *DyClient) sendMessage(ctx context.Context, conv *Conversation, msgType int, content string) (int64, error) {
+	clientMsgID := randomString(8) + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	body := conversationRef(conv)
+	body = pb.AppendString(body, 4, content)
+	body = appendStringMap(body, 5, map[string]string{
+		"s:mentioned_users":   "",
+		"s:client_message_id": clientMsgID,
+	})
+	body = pb.AppendVarint(body, 6, uint64(msgType))
+	body = pb.AppendString(body, 7, conv.Ticket)
+	body = pb.AppendString(body, 8, clientMsgID)
+
+	data, err := c.imCall(ctx, "/v1/message/send", cmdSendMessage, body)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		serverMsgID  int64
+		status       int
+		checkMessage string
+	)
+	err = pb.Walk(data, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			serverMsgID = f.Int64()
+		case 3:
+			status = int(f.Varint)
+		case 6:
+			checkMessage = f.String()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("decode send message response failed: %w", err)
+	}
+	if status != 0 {
+		return 0, fmt.Errorf("send message failed, status: %d, msg: %s", status, checkMessage)
+	}
+	return serverMsgID, nil
+}
+
+// MarkRead 将会话中 index 及之前的消息标记为已读
+func (c *DyClient) MarkRead(ctx context.Context, conv *Conversation, index int64) error {
+	var body []byte
+	body = pb.AppendString(body, 1, conv.ID)
+	body = pb.AppendVarint(body, 2, uint64(conv.ShortID))
+	body = pb.AppendVarint(body, 3, uint64(conv.Type))
+	body = pb.AppendVarint(body, 4, uint64(index))
+
+	_, err := c.imCall(ctx, "/v1/conversation/mark_read", cmdMarkConversationRead, body)
+	return err
+}
+
+// MessageWatcher 轮询获取新私信
+//
+//	w, err := client.WatchMessages(ctx, 5*time.Second)
+//	for m := range w.Messages() {
+//		...
+//	}
+//	err = w.Err()
+type MessageWatcher struct {
+	messages chan DirectMessage
+	cancel   context.CancelFunc
+	done     chan struct{}
+	err      error
+}
+
+// WatchMessages 以 interval 为间隔轮询收件箱，只返回开始监听之后收到的消息
+// ctx 取消或调用 Close 时通道关闭，请求失败时通道关闭并可通过 Err 获取错误
+func (c *DyClient) WatchMessages(ctx context.Context, interval time.Duration) (*MessageWatcher, error) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	cursor, err := c.inboxCursor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &MessageWatcher{
+		messages: make(chan DirectMessage, 64),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		defer close(w.messages)
+		defer cancel()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			next, err := drainInbox(ctx, cursor, c.messagesByUser, w.messages)
+			cursor = next
+			if err != nil {
+				if ctx.Err() == nil {
+					w.err = err
+				}
+				return
+			}
+		}
+	}()
+	return w, nil
+}
+
+// drainInbox 从 cursor 开始连续拉取新消息直到没有更多，返回新的游标
+// 服务端返回 has_more 但游标未前进时停止，等下一次轮询再继续，避免原地反复请求
+func drainInbox(ctx context.Context, cursor int64,
+	fetch func(ctx context.Context, cursor int64) ([]DirectMessage, int64, bool, error),
+	out chan<- DirectMessage) (int64, error) {
+	for {
+		messages, next, hasMore, err := fetch(ctx, cursor)
+		if err != nil {
+			return cursor, err
+		}
+		for _, m := range messages {
+			select {
+			case out <- m:
+			case <-ctx.Done():
+				return next, ctx.Err()
+			}
+		}
+		if !hasMore || next == cursor {
+			return next, nil
+		}
+		cursor = next
+	}
+}
+
+// Messages 新消息通道
+func (w *MessageWatcher) Messages() <-chan DirectMessage {
+	return w.messages
+}
+
+// Err 在消息通道关闭后返回导致轮询结束的错误，正常结束时为 nil
+func (w *MessageWatcher) Err() error {
+	<-w.done
+	return w.err
+}
+
+// Close 停止轮询并等待消息通道关闭
+func (w *MessageWatcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+// inboxCursor 获取当前收件箱的游标
+func (c *DyClient) inboxCursor(ctx context.Context) (int64, error) {
+	data, err := c.imCall(ctx, "/v1/message/get_by_user_init", cmdGetMessagesByUserInit, pb.AppendVarint(nil, 1, 0))
+	if err != nil {
+		return 0, err
+	}
+	var cursor int64
+	err = pb.Walk(data, func(f pb.Field) error {
+		if f.Num == 3 {
+			cursor = f.Int64()
+		}
+		return nil
+	})
+	return cursor, err
+}
+
+// messagesByUser 获取收件箱中 cursor 之后的新消息
+func (c *DyClient) messagesByUser(ctx context.Context, cursor int64) ([]DirectMessage, int64, bool, error) {
+	data, err := c.imCall(ctx, "/v1/message/get_by_user", cmdGetMessagesByUser, pb.AppendVarint(nil, 1, uint64(cursor)))
+	if err != nil {
+		return nil, cursor, false, err
+	}
+	var (
+		messages []DirectMessage
+		next     = cursor
+		hasMore  bool
+	)
+	err = pb.Walk(data, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			m, err := parseMessageBody(f.Bytes)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, m)
+		case 2:
+			next = f.Int64()
+		case 3:
+			hasMore = f.Bool()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, cursor, false, fmt.Errorf("decode inbox messages failed: %w", err)
+	}
+	return messages, next, hasMore, nil
+}
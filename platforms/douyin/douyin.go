@@ -6,7 +6,6 @@ import (
 	"crawler-sdk/internal/crypto/dy/auth"
 	"crawler-sdk/internal/crypto/dy/bd"
 	"crawler-sdk/pkg/http"
-	"math/rand"
 	"net/url"
 
 	"fmt"
@@ -18,37 +17,116 @@ import (
 // AuthDetails holds the temporary credentials for uploading.
 // It's derived from the /web/api/media/upload/auth/v5/ endpoint response.
 
+const douyinWebURL = "https://www.douyin.com"
+
+// BaseResponse 抖音接口通用的状态字段
+type BaseResponse struct {
+	StatusCode int    `json:"status_code"`
+	StatusMsg  string `json:"status_msg"`
+}
+
+// check 在 status_code 非 0 时返回错误
+func (r *BaseResponse) check(action string) error {
+	if r.StatusCode == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s failed, status code: %d, msg: %s", action, r.StatusCode, r.StatusMsg)
+}
+
 // DyClient 是抖音平台的客户端
 type DyClient struct {
 	client       *resty.Client
 	imagexClient *resty.Client
+	vodClient    *resty.Client
 	uploadClient *resty.Client
+	imClient     *resty.Client
+	cookie       string
+	msToken      *msTokenCache
+	// webID、verifyFp 游客会话的设备标识，登录态客户端为空
+	webID    string
+	verifyFp string
 }
 
 // New 创建一个新的抖音客户端实例
 // cookie: 用于身份验证的抖音 cookie 字符串
-func New(cookie string, bdSigner *bd.BDSigner) *DyClient {
+// bdSigner: 账号的 ticket-guard 请求头生成器，*bd.BDSigner (v2) 或 *bd.BDTGKeyPair (v3)，游客会话传 nil
+func New(cookie string, bdSigner bd.HeaderBuilder, opts ...Option) *DyClient {
 	c := &DyClient{
 		client:       http.NewClient(cookie),
 		imagexClient: http.NewClient(""),
+		vodClient:    http.NewClient(""),
 		uploadClient: http.NewClient(""),
+		imClient:     http.NewClient(cookie),
+		cookie:       cookie,
+		msToken:      newMsTokenCache(cookie),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
 	c.client.SetContext(context.WithValue(context.Background(), "bdSigner", bdSigner))
 	c.client.AddRequestMiddleware(headers)
-	c.client.AddRequestMiddleware(msToken)
+	c.client.AddRequestMiddleware(c.msTokenMiddleware)
 	c.client.AddRequestMiddleware(absign)
 	c.client.AddRequestMiddleware(bdSign)
+	c.client.AddResponseMiddleware(c.msTokenUpdater)
 	c.imagexClient.AddRequestMiddleware(uploadSign)
 	c.imagexClient.AddRequestMiddleware(uploadHeaders)
+	c.vodClient.AddRequestMiddleware(vodSign)
+	c.vodClient.AddRequestMiddleware(vodHeaders)
 	// c.uploadClient.AddRequestMiddleware(uploadSign)
 	// c.uploadClient.AddRequestMiddleware(uploadHeaders)
 	return c
 }
 
-func msToken(c *resty.Client, req *resty.Request) error {
-	msToken := getMsToken(120)
-	req.SetQueryParam("msToken", msToken)
-	return nil
+// rawClient 返回与 c.client 共用 Transport (含代理) 与请求头 (User-Agent、Cookie) 的客户端，
+// 不经过 msToken、a_bogus 等签名中间件，用于游客注册、CDN 下载等非网页端 API 请求
+func (c *DyClient) rawClient() *resty.Client {
+	client := resty.New().SetTransport(c.client.Transport())
+	for k, v := range c.client.Header() {
+		client.Header()[k] = append([]string(nil), v...)
+	}
+	return client
+}
+
+// webQueryParams www.douyin.com 网页端接口通用的查询参数
+func (c *DyClient) webQueryParams(extra map[string]string) map[string]string {
+	params := map[string]string{
+		"device_platform":  "webapp",
+		"aid":              "6383",
+		"channel":          "channel_pc_web",
+		"pc_client_type":   "1",
+		"version_code":     "290100",
+		"version_name":     "29.1.0",
+		"cookie_enabled":   "true",
+		"screen_width":     "2048",
+		"screen_height":    "1152",
+		"browser_language": "zh-CN",
+		"browser_platform": "Win32",
+		"browser_name":     "Edge",
+		"browser_version":  "139.0.0.0",
+		"browser_online":   "true",
+		"engine_name":      "Blink",
+		"engine_version":   "139.0.0.0",
+		"os_name":          "Windows",
+		"os_version":       "10",
+		"cpu_core_num":     "12",
+		"device_memory":    "8",
+		"platform":         "PC",
+		"downlink":         "10",
+		"effective_type":   "4g",
+		"round_trip_time":  "50",
+	}
+	if c.webID != "" {
+		params["webid"] = c.webID
+	}
+	if c.verifyFp != "" {
+		params["verifyFp"] = c.verifyFp
+		params["fp"] = c.verifyFp
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
+	return params
 }
 
 func absign(c *resty.Client, req *resty.Request) error {
@@ -62,27 +140,6 @@ func absign(c *resty.Client, req *resty.Request) error {
 	return nil
 }
 
-// def get_ms_token(randomlength=120):
-// """
-// 根据传入长度产生随机字符串
-// """
-// random_str = ''
-// base_str = 'ABCDEFGHIGKLMNOPQRSTUVWXYZabcdefghigklmnopqrstuvwxyz0123456789='
-// length = len(base_str) - 1
-// for _ in range(randomlength):
-// 	random_str += base_str[random.randint(0, length)]
-// return random_str
-
-func getMsToken(randomlength int) string {
-	random_str := ""
-	base_str := "ABCDEFGHIGKLMNOPQRSTUVWXYZabcdefghigklmnopqrstuvwxyz0123456789="
-	length := len(base_str) - 1
-	for range randomlength {
-		random_str += string(base_str[rand.Intn(length)])
-	}
-	return random_str
-}
-
 func headers(c *resty.Client, req *resty.Request) error {
 	fmt.Println("headers")
 	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8,en-GB;q=0.7,en-US;q=0.6")
@@ -99,7 +156,18 @@ func headers(c *resty.Client, req *resty.Request) error {
 }
 
 func uploadHeaders(c *resty.Client, req *resty.Request) error {
-	req.Header.Set("Host", "imagex.bytedanceapi.com")
+	setUploadHeaders(req, "imagex.bytedanceapi.com")
+	return nil
+}
+
+func vodHeaders(c *resty.Client, req *resty.Request) error {
+	setUploadHeaders(req, "vod.bytedanceapi.com")
+	return nil
+}
+
+// setUploadHeaders 设置 ImageX / VOD 上传接口共用的请求头
+func setUploadHeaders(req *resty.Request, host string) {
+	req.Header.Set("Host", host)
 	req.Header.Set("Connection", "keep-alive")
 	req.Header.Set("sec-ch-ua-platform", "Windows")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36 Edg/139.0.0.0")
@@ -113,40 +181,45 @@ func uploadHeaders(c *resty.Client, req *resty.Request) error {
 	req.Header.Set("Referer", "https://creator.douyin.com/")
 	req.Header.Set("Accept-Encoding", "gzip, deflate, br, zstd")
 	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8,en-GB;q=0.7,en-US;q=0.6")
-	return nil
 }
 
 func uploadSign(c *resty.Client, req *resty.Request) error {
 	fmt.Println("uploadSign")
+	signUpload(req, "imagex")
+	return nil
+}
+
+func vodSign(c *resty.Client, req *resty.Request) error {
+	signUpload(req, "vod")
+	return nil
+}
+
+// signUpload 使用上传凭证对 ImageX / VOD 请求进行 AWS v4 签名
+func signUpload(req *resty.Request, service string) {
 	a := req.Context().Value("auth").(*AuthDetails)
-	signer := auth.NewSigner(req, "imagex", "cn-north-1", false)
+	signer := auth.NewSigner(req, service, "cn-north-1", false)
 	credentials := auth.Credentials{
 		AccessKeyID:     a.AccessKeyID,
 		SecretAccessKey: a.SecretAccessKey,
 		SessionToken:    a.SessionToken,
 	}
 	signer.AddAuthorization(credentials, time.Now())
-	return nil
 }
 
 func bdSign(c *resty.Client, req *resty.Request) error {
-	fmt.Println("bdSign")
+	b, _ := c.Context().Value("bdSigner").(bd.HeaderBuilder)
+	if b == nil {
+		// 游客会话没有 ticket-guard 凭证
+		return nil
+	}
 	u, err := url.Parse(req.URL)
 	if err != nil {
 		return err
 	}
-	b := c.Context().Value("bdSigner").(*bd.BDSigner)
-	s, err := b.BDSign(u.Path)
+	headers, err := b.Headers(u.Path)
 	if err != nil {
 		return err
 	}
-
-	req.SetHeader("bd-ticket-guard-client-data", s)
-	req.SetHeader("bd-ticket-guard-web-sign-type", "1")
-	req.SetHeader("bd-ticket-guard-iteration-version", "1")
-	req.SetHeader("bd-ticket-guard-ree-public-key", "b.EcPublicKey")
-	req.SetHeader("bd-ticket-guard-version", "2")
-	req.SetHeader("bd-ticket-guard-web-version", "2")
-
+	req.SetHeaders(headers)
 	return nil
 }
@@ -0,0 +1,400 @@
+package douyin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// GetAwemeDetail 获取作品详情
+func (c *DyClient) GetAwemeDetail(ctx context.Context, awemeID string) (*Aweme, error) {
+	var result struct {
+		BaseResponse
+		AwemeDetail *Aweme `json:"aweme_detail"`
+	}
+	_, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", douyinWebURL+"/video/"+awemeID).
+		SetQueryParams(c.webQueryParams(map[string]string{
+			"aweme_id": awemeID,
+		})).
+		Get(douyinWebURL + "/aweme/v1/web/aweme/detail/")
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get aweme detail"); err != nil {
+		return nil, err
+	}
+	if result.AwemeDetail == nil {
+		return nil, fmt.Errorf("aweme %s not found", awemeID)
+	}
+	return result.AwemeDetail, nil
+}
+
+// DefaultNameTemplate 默认的文件命名模板
+const DefaultNameTemplate = "{author}_{aweme_id}_{date}"
+
+// DownloadOptions 下载选项
+type DownloadOptions struct {
+	// Dir 保存目录，默认为当前目录
+	Dir string
+	// NameTemplate 文件名模板 (不含扩展名)，支持 {author}、{author_id}、{aweme_id}、{date}、{desc} 占位符
+	NameTemplate string
+	// Concurrency 同时进行的下载数，默认 3
+	Concurrency int
+	// SkipMusic 不下载背景音乐
+	SkipMusic bool
+}
+
+// DownloadResult 单个作品的下载结果
+type DownloadResult struct {
+	AwemeID string
+	// Files 已保存的文件路径
+	Files []string
+}
+
+// Downloader 作品下载器，与创建它的 DyClient 使用相同的 cookie 与代理
+type Downloader struct {
+	client *resty.Client
+	opts   DownloadOptions
+	sem    chan struct{}
+}
+
+// NewDownloader 创建下载器
+func (c *DyClient) NewDownloader(opts *DownloadOptions) *Downloader {
+	o := DownloadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.NameTemplate == "" {
+		o.NameTemplate = DefaultNameTemplate
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 3
+	}
+	// 与接口请求共用代理与 Cookie，CDN 请求从同一出口发出
+	client := c.rawClient()
+	client.SetHeader("Referer", douyinWebURL+"/")
+	return &Downloader{
+		client: client,
+		opts:   o,
+		sem:    make(chan struct{}, o.Concurrency),
+	}
+}
+
+// downloadSource 一个候选下载地址，size 为该地址对应文件的已知大小，0 表示未知
+type downloadSource struct {
+	url  string
+	size int64
+}
+
+// downloadTask 一个待下载的文件，sources 为按优先级排列的候选地址
+type downloadTask struct {
+	sources []downloadSource
+	path    string
+}
+
+// urlSources 将大小未知的地址列表转换为候选地址
+func urlSources(urls []string) []downloadSource {
+	sources := make([]downloadSource, 0, len(urls))
+	for _, u := range urls {
+		sources = append(sources, downloadSource{url: u})
+	}
+	return sources
+}
+
+// Download 下载作品的视频 (或图文的全部图片) 以及背景音乐
+func (d *Downloader) Download(ctx context.Context, aweme *Aweme) (*DownloadResult, error) {
+	tasks, err := d.tasks(aweme)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(d.dir(), 0o755); err != nil {
+		return nil, err
+	}
+
+	result := &DownloadResult{AwemeID: aweme.AwemeID}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, t := range tasks {
+		wg.Add(1)
+		go func(t downloadTask) {
+			defer wg.Done()
+			select {
+			case d.sem <- struct{}{}:
+			case <-ctx.Done():
+				mu.Lock()
+				errs = append(errs, ctx.Err())
+				mu.Unlock()
+				return
+			}
+			defer func() { <-d.sem }()
+
+			err := d.fetch(ctx, t)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("download %s failed: %w", filepath.Base(t.path), err))
+				return
+			}
+			result.Files = append(result.Files, t.path)
+		}(t)
+	}
+	wg.Wait()
+	sort.Strings(result.Files)
+	return result, errors.Join(errs...)
+}
+
+// DownloadAll 并发下载多个作品，并发数由 Concurrency 统一限制
+func (d *Downloader) DownloadAll(ctx context.Context, awemes []*Aweme) ([]*DownloadResult, error) {
+	results := make([]*DownloadResult, len(awemes))
+	errs := make([]error, len(awemes))
+	var wg sync.WaitGroup
+	for i, aweme := range awemes {
+		wg.Add(1)
+		go func(i int, aweme *Aweme) {
+			defer wg.Done()
+			results[i], errs[i] = d.Download(ctx, aweme)
+		}(i, aweme)
+	}
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
+
+func (d *Downloader) dir() string {
+	if d.opts.Dir == "" {
+		return "."
+	}
+	return d.opts.Dir
+}
+
+// tasks 生成作品需要下载的文件列表
+func (d *Downloader) tasks(aweme *Aweme) ([]downloadTask, error) {
+	base := filepath.Join(d.dir(), d.fileName(aweme))
+	var tasks []downloadTask
+
+	if aweme.IsImagePost() {
+		for i, img := range aweme.Images {
+			if len(img.URLList) == 0 {
+				continue
+			}
+			tasks = append(tasks, downloadTask{
+				sources: urlSources(img.URLList),
+				path:    fmt.Sprintf("%s_%d%s", base, i+1, imageExt(img.URLList[0])),
+			})
+		}
+	} else {
+		sources := bestVideoSources(&aweme.Video)
+		if len(sources) == 0 {
+			return nil, fmt.Errorf("no playable video url in aweme %s", aweme.AwemeID)
+		}
+		tasks = append(tasks, downloadTask{sources: sources, path: base + ".mp4"})
+	}
+
+	if !d.opts.SkipMusic && aweme.Music != nil && len(aweme.Music.PlayURL.URLList) > 0 {
+		tasks = append(tasks, downloadTask{
+			sources: urlSources(aweme.Music.PlayURL.URLList),
+			path:    base + "_music.mp3",
+		})
+	}
+	return tasks, nil
+}
+
+// fileName 按模板生成文件名
+func (d *Downloader) fileName(aweme *Aweme) string {
+	desc := []rune(aweme.Desc)
+	if len(desc) > 30 {
+		desc = desc[:30]
+	}
+	name := strings.NewReplacer(
+		"{author}", aweme.Author.Nickname,
+		"{author_id}", aweme.Author.UID,
+		"{aweme_id}", aweme.AwemeID,
+		"{date}", time.Unix(aweme.CreateTime, 0).Format("20060102"),
+		"{desc}", string(desc),
+	).Replace(d.opts.NameTemplate)
+	return sanitizeFileName(name)
+}
+
+// sanitizeFileName 替换文件名中不合法的字符
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', '\n', '\r', '\t':
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "aweme"
+	}
+	return name
+}
+
+// bestVideoSources 返回无水印的视频地址，按清晰度从高到低排列
+// 优先使用 bit_rate 中码率最高的档位 (同码率时优先 H.264)，再回退到 play_addr
+// 每个地址带上其所属档位的 data_size，不同档位的文件大小不同
+func bestVideoSources(v *Video) []downloadSource {
+	rates := make([]BitRate, len(v.BitRate))
+	copy(rates, v.BitRate)
+	sort.SliceStable(rates, func(i, j int) bool {
+		if rates[i].BitRate != rates[j].BitRate {
+			return rates[i].BitRate > rates[j].BitRate
+		}
+		return rates[i].IsH265 < rates[j].IsH265
+	})
+
+	var sources []downloadSource
+	seen := map[string]bool{}
+	add := func(addr URLInfo) {
+		for _, u := range addr.URLList {
+			u = strings.Replace(u, "/playwm/", "/play/", 1)
+			if u != "" && !seen[u] {
+				seen[u] = true
+				sources = append(sources, downloadSource{url: u, size: addr.DataSize})
+			}
+		}
+	}
+	for _, r := range rates {
+		add(r.PlayAddr)
+	}
+	add(v.PlayAddr)
+	return sources
+}
+
+// imageExt 根据图片地址推断扩展名
+func imageExt(u string) string {
+	if i := strings.IndexByte(u, '?'); i >= 0 {
+		u = u[:i]
+	}
+	for _, ext := range []string{".webp", ".jpeg", ".jpg", ".png", ".heic"} {
+		if strings.Contains(u, ext) {
+			return ext
+		}
+	}
+	return ".jpg"
+}
+
+// fetch 依次尝试候选地址下载文件
+func (d *Downloader) fetch(ctx context.Context, t downloadTask) error {
+	if info, err := os.Stat(t.path); err == nil && info.Size() > 0 {
+		return nil
+	}
+	var errs []error
+	for i, src := range t.sources {
+		err := d.fetchURL(ctx, src.url, t.path, src.size)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		errs = append(errs, err)
+		// 换用下一个地址前丢弃未完成的数据，避免拼接不同来源的内容
+		if i < len(t.sources)-1 {
+			os.Remove(t.path + ".part")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fetchURL 断点续传下载单个地址，未完成的数据保存在 .part 文件中
+// expected: 已知的文件大小，0 表示以服务端返回为准
+func (d *Downloader) fetchURL(ctx context.Context, u, path string, expected int64) error {
+	part := path + ".part"
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	req := d.client.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true)
+	if offset > 0 {
+		req.SetHeader("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := req.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var total int64
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		// 服务端不支持 Range，从头开始
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		offset = 0
+		total = resp.RawResponse.ContentLength
+	case http.StatusPartialContent:
+		total = contentRangeTotal(resp.Header().Get("Content-Range"))
+	case http.StatusRequestedRangeNotSatisfiable:
+		// .part 已是完整文件
+		total = contentRangeTotal(resp.Header().Get("Content-Range"))
+		if total <= 0 || total != offset {
+			f.Truncate(0)
+			return fmt.Errorf("range not satisfiable at offset %d", offset)
+		}
+	default:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode())
+	}
+
+	if resp.StatusCode() != http.StatusRequestedRangeNotSatisfiable {
+		n, err := io.Copy(f, resp.Body)
+		if err != nil {
+			return err
+		}
+		offset += n
+	}
+
+	if total <= 0 {
+		total = expected
+	}
+	if total > 0 && offset != total {
+		return fmt.Errorf("size mismatch: got %d bytes, expected %d", offset, total)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(part, path)
+}
+
+// contentRangeTotal 解析 "bytes 0-99/1000" 或 "bytes */1000" 中的总大小
+func contentRangeTotal(v string) int64 {
+	i := strings.LastIndexByte(v, '/')
+	if i < 0 {
+		return 0
+	}
+	total, err := strconv.ParseInt(v[i+1:], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return total
+}
@@ -0,0 +1,209 @@
+package douyin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"resty.dev/v3"
+)
+
+var fileContent = bytes.Repeat([]byte("0123456789"), 100)
+
+// rangeServer 返回支持 Range 请求的文件服务，ignoreRange 为 true 时总是返回完整内容
+func rangeServer(t *testing.T, content []byte, ignoreRange bool) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rng := r.Header.Get("Range")
+		if rng == "" || ignoreRange {
+			w.Write(content)
+			return
+		}
+		start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if err != nil {
+			http.Error(w, "bad range", http.StatusBadRequest)
+			return
+		}
+		if start >= len(content) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(content)))
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(content[start:])
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testDownloader() *Downloader {
+	return &Downloader{client: resty.New(), sem: make(chan struct{}, 1)}
+}
+
+func checkFile(t *testing.T, path string, want []byte) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s has %d bytes, want %d", filepath.Base(path), len(got), len(want))
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf(".part file still exists")
+	}
+}
+
+func TestNewDownloaderInheritsClient(t *testing.T) {
+	c := New("sessionid=x", nil, WithProxy("http://10.0.0.1:3128"))
+	d := c.NewDownloader(nil)
+	transport, err := d.client.HTTPTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://v26-web.douyinvod.com/video.mp4", nil)
+	if u, err := transport.Proxy(req); err != nil || u == nil || u.Host != "10.0.0.1:3128" {
+		t.Errorf("downloader proxy = %v, %v", u, err)
+	}
+	if got := d.client.Header().Get("Cookie"); got != c.client.Header().Get("Cookie") {
+		t.Errorf("Cookie = %q, want %q", got, c.client.Header().Get("Cookie"))
+	}
+	if got := d.client.Header().Get("Referer"); got != douyinWebURL+"/" {
+		t.Errorf("Referer = %q", got)
+	}
+}
+
+func TestFetchURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		partial     []byte
+		ignoreRange bool
+	}{
+		{"fresh download", nil, false},
+		{"resume with range", fileContent[:300], false},
+		{"server ignores range", []byte("stale data"), true},
+		{"part already complete", fileContent, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := rangeServer(t, fileContent, tt.ignoreRange)
+			path := filepath.Join(t.TempDir(), "video.mp4")
+			if tt.partial != nil {
+				if err := os.WriteFile(path+".part", tt.partial, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := testDownloader().fetchURL(context.Background(), srv.URL, path, int64(len(fileContent))); err != nil {
+				t.Fatalf("fetchURL() error = %v", err)
+			}
+			checkFile(t, path, fileContent)
+		})
+	}
+}
+
+func TestFetchURLRangeNotSatisfiable(t *testing.T) {
+	// .part 比服务端文件更长，说明不是同一个文件，应丢弃
+	srv := rangeServer(t, fileContent, false)
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(path+".part", append(fileContent, 'x'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := testDownloader().fetchURL(context.Background(), srv.URL, path, 0); err == nil {
+		t.Fatal("fetchURL() expected error")
+	}
+	if info, err := os.Stat(path + ".part"); err != nil || info.Size() != 0 {
+		t.Errorf(".part should be truncated, stat = %v, %v", info, err)
+	}
+}
+
+func TestFetchURLSizeMismatch(t *testing.T) {
+	// 分块传输没有 Content-Length，只能与已知大小比较
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(fileContent[:500])
+		w.(http.Flusher).Flush()
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	err := testDownloader().fetchURL(context.Background(), srv.URL, path, int64(len(fileContent)))
+	if err == nil || !strings.Contains(err.Error(), "size mismatch") {
+		t.Fatalf("fetchURL() error = %v, want size mismatch", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("incomplete file should not be renamed")
+	}
+}
+
+func TestFetchFallbackUsesSourceSize(t *testing.T) {
+	small := fileContent[:400]
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusForbidden)
+	}))
+	defer bad.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(small)
+		w.(http.Flusher).Flush()
+	}))
+	defer fallback.Close()
+
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	err := testDownloader().fetch(context.Background(), downloadTask{
+		path: path,
+		sources: []downloadSource{
+			{url: bad.URL, size: int64(len(fileContent))},
+			{url: fallback.URL, size: int64(len(small))},
+		},
+	})
+	if err != nil {
+		t.Fatalf("fetch() error = %v", err)
+	}
+	checkFile(t, path, small)
+}
+
+func TestBestVideoSources(t *testing.T) {
+	v := &Video{
+		BitRate: []BitRate{
+			{BitRate: 1000, IsH265: 1, PlayAddr: URLInfo{URLList: []string{"https://a/h265_1000"}, DataSize: 100}},
+			{BitRate: 2000, IsH265: 1, PlayAddr: URLInfo{URLList: []string{"https://a/h265_2000"}, DataSize: 200}},
+			{BitRate: 2000, IsH265: 0, PlayAddr: URLInfo{URLList: []string{"https://a/h264_2000", "https://b/h264_2000"}, DataSize: 210}},
+		},
+		PlayAddr: URLInfo{URLList: []string{"https://a/playwm/x", "https://a/h265_1000"}, DataSize: 90},
+	}
+	want := []downloadSource{
+		{"https://a/h264_2000", 210},
+		{"https://b/h264_2000", 210},
+		{"https://a/h265_2000", 200},
+		{"https://a/h265_1000", 100},
+		{"https://a/play/x", 90},
+	}
+	got := bestVideoSources(v)
+	if len(got) != len(want) {
+		t.Fatalf("bestVideoSources() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("source %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestContentRangeTotal(t *testing.T) {
+	for in, want := range map[string]int64{
+		"bytes 0-99/1000": 1000,
+		"bytes */1000":    1000,
+		"bytes 0-99/*":    0,
+		"":                0,
+	} {
+		if got := contentRangeTotal(in); got != want {
+			t.Errorf("contentRangeTotal(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package douyin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"resty.dev/v3"
+)
+
+const (
+	ttwidRegisterURL = "https://ttwid.bytedance.com/ttwid/union/register/"
+	webIDURL         = "https://mcs.zijieapi.com/webid"
+)
+
+// GuestSession 游客会话的身份信息，可保存后通过 NewGuestFromSession 复用
+type GuestSession struct {
+	TTWID    string `json:"ttwid"`
+	VerifyFp string `json:"verify_fp"`
+	WebID    string `json:"webid"`
+}
+
+// Cookie 游客会话对应的 cookie 字符串
+func (s *GuestSession) Cookie() string {
+	return fmt.Sprintf("ttwid=%s; s_v_web_id=%s", s.TTWID, s.VerifyFp)
+}
+
+// NewGuest 创建一个不登录的游客客户端，可调用主页、作品、评论等只读接口
+// 每次调用都会注册新的 ttwid 与 webid，大规模采集时可为每个 worker 创建独立的游客客户端
+// opts 同时作用于注册请求，如 WithProxy 设置的代理
+func NewGuest(ctx context.Context, opts ...Option) (*DyClient, error) {
+	session, err := NewGuestSession(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewGuestFromSession(session, opts...), nil
+}
+
+// NewGuestFromSession 使用已有的游客会话创建客户端
+func NewGuestFromSession(session *GuestSession, opts ...Option) *DyClient {
+	c := New(session.Cookie(), nil, opts...)
+	c.webID = session.WebID
+	c.verifyFp = session.VerifyFp
+	return c
+}
+
+// NewGuestSession 注册游客身份：获取 ttwid、生成 s_v_web_id (verifyFp) 并申请 webid
+// 注册请求使用按 opts 配置的客户端发出，与之后的接口请求走同一代理
+func NewGuestSession(ctx context.Context, opts ...Option) (*GuestSession, error) {
+	client := New("", nil, opts...).rawClient()
+	ttwid, err := registerTTWID(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	webID, err := registerWebID(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return &GuestSession{
+		TTWID:    ttwid,
+		VerifyFp: genVerifyFp(),
+		WebID:    webID,
+	}, nil
+}
+
+// registerTTWID 通过 ttwid 注册接口获取 ttwid，ttwid 在响应的 Set-Cookie 中下发
+func registerTTWID(ctx context.Context, client *resty.Client) (string, error) {
+	resp, err := client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"region":        "cn",
+			"aid":           1768,
+			"needFid":       false,
+			"service":       "www.ixigua.com",
+			"migrate_info":  map[string]string{"ticket": "", "source": "node"},
+			"cbUrlProtocol": "https",
+			"union":         true,
+		}).
+		Post(ttwidRegisterURL)
+	if err != nil {
+		return "", err
+	}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "ttwid" && cookie.Value != "" {
+			return cookie.Value, nil
+		}
+	}
+	return "", errors.New("register ttwid failed: no ttwid in response")
+}
+
+// registerWebID 申请网页端的 webid (设备 ID)
+func registerWebID(ctx context.Context, client *resty.Client) (string, error) {
+	var result struct {
+		E     int    `json:"e"`
+		WebID string `json:"web_id"`
+	}
+	_, err := client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Content-Type", "application/json; charset=UTF-8").
+		SetHeader("Referer", douyinWebURL+"/").
+		SetQueryParams(map[string]string{
+			"aid":             "6383",
+			"sdk_version":     "5.1.18_zip",
+			"device_platform": "web",
+		}).
+		SetBody(map[string]any{
+			"app_id":         6383,
+			"referer":        douyinWebURL + "/",
+			"url":            douyinWebURL + "/",
+			"user_agent":     client.Header().Get("User-Agent"),
+			"user_unique_id": "",
+		}).
+		Post(webIDURL)
+	if err != nil {
+		return "", err
+	}
+	if result.WebID == "" {
+		return "", fmt.Errorf("register webid failed, code: %d", result.E)
+	}
+	return result.WebID, nil
+}
+
+// genVerifyFp 生成 s_v_web_id / verifyFp，格式为 verify_<毫秒时间戳 base36>_<36 位随机串>
+func genVerifyFp() string {
+	const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	r := make([]byte, 36)
+	for i := range r {
+		switch i {
+		case 8, 13, 18, 23:
+			r[i] = '_'
+		case 14:
+			r[i] = '4'
+		case 19:
+			r[i] = alphabet[rand.Intn(len(alphabet))&3|8]
+		default:
+			r[i] = alphabet[rand.Intn(len(alphabet))]
+		}
+	}
+	ts := strconv.FormatInt(time.Now().UnixMilli(), 36)
+	return "verify_" + ts + "_" + string(r)
+}
@@ -0,0 +1,110 @@
+package douyin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withTransport 让客户端的接口请求与游客注册请求都交给 rt 处理
+func withTransport(rt http.RoundTripper) Option {
+	return func(c *DyClient) {
+		c.client.SetTransport(rt)
+	}
+}
+
+// guestTransport 模拟 ttwid 与 webid 注册接口
+func guestTransport(t *testing.T, webIDBody string, setTTWID bool) http.RoundTripper {
+	return roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Host + r.URL.Path {
+		case "ttwid.bytedance.com/ttwid/union/register/":
+			if body["aid"] != float64(1768) || body["union"] != true {
+				t.Errorf("ttwid register body = %v", body)
+			}
+			resp := jsonResponse(r, `{"status_code":0}`)
+			if setTTWID {
+				resp.Header.Add("Set-Cookie", "ttwid=1%7Cabc; Path=/; Domain=bytedance.com")
+			}
+			return resp, nil
+		case "mcs.zijieapi.com/webid":
+			if r.URL.Query().Get("aid") != "6383" || body["app_id"] != float64(6383) || body["user_agent"] != r.Header.Get("User-Agent") {
+				t.Errorf("webid request = %s, body = %v", r.URL, body)
+			}
+			return jsonResponse(r, webIDBody), nil
+		}
+		t.Errorf("unexpected request %s", r.URL)
+		return jsonResponse(r, `{}`), nil
+	})
+}
+
+func TestNewGuest(t *testing.T) {
+	c, err := NewGuest(context.Background(), withTransport(guestTransport(t, `{"e":0,"web_id":"7400000000000000001"}`, true)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.webID != "7400000000000000001" || !strings.HasPrefix(c.verifyFp, "verify_") {
+		t.Errorf("guest webID = %q, verifyFp = %q", c.webID, c.verifyFp)
+	}
+	if cookie := c.client.Header().Get("Cookie"); !strings.HasPrefix(cookie, "ttwid=1%7Cabc; s_v_web_id=verify_") {
+		t.Errorf("guest cookie = %q", cookie)
+	}
+}
+
+func TestNewGuestSessionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		webID    string
+		setTTWID bool
+		wantErr  string
+	}{
+		{"no ttwid cookie", `{"e":0,"web_id":"1"}`, false, "no ttwid in response"},
+		{"webid rejected", `{"e":-1}`, true, "register webid failed, code: -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGuestSession(context.Background(), withTransport(guestTransport(t, tt.webID, tt.setTTWID)))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewGuestSession() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGuestClientUsesProxy(t *testing.T) {
+	c := New("", nil, WithProxy("http://10.0.0.1:3128"))
+	transport, err := c.rawClient().HTTPTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, ttwidRegisterURL, nil)
+	if u, err := transport.Proxy(req); err != nil || u == nil || u.Host != "10.0.0.1:3128" {
+		t.Errorf("guest client proxy = %v, %v", u, err)
+	}
+	if got := c.msToken.client.ProxyURL(); got == nil || got.Host != "10.0.0.1:3128" {
+		t.Errorf("msToken client proxy = %v", got)
+	}
+}
+
+func TestGenVerifyFp(t *testing.T) {
+	re := regexp.MustCompile(`^verify_([0-9a-z]+)_[0-9A-Za-z]{8}_[0-9A-Za-z]{4}_4[0-9A-Za-z]{3}_[89AB][0-9A-Za-z]{3}_[0-9A-Za-z]{12}$`)
+	before := time.Now().UnixMilli()
+	fp := genVerifyFp()
+	m := re.FindStringSubmatch(fp)
+	if m == nil {
+		t.Fatalf("genVerifyFp() = %q, does not match %s", fp, re)
+	}
+	ts, err := strconv.ParseInt(m[1], 36, 64)
+	if err != nil || ts < before || ts > time.Now().UnixMilli() {
+		t.Errorf("timestamp %q = %d, want current time in base36", m[1], ts)
+	}
+	if genVerifyFp() == fp {
+		t.Error("genVerifyFp() returned the same value twice")
+	}
+}
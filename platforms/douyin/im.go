@@ -0,0 +1,210 @@
+package douyin
+
+import (
+	"bytes"
+	"context"
+	"crawler-sdk/internal/pb"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// 网页端私信 (IM) 接口使用 protobuf 编码的 Request/Response，
+// 请求体 RequestBody 与响应体 ResponseBody 中子消息的字段号与 cmd 相同
+const (
+	imAPIURL = "https://imapi.douyin.com"
+
+	imSDKVersion  = "1.1.3"
+	imBuildNumber = "0c1e3a9:p/lzg/store"
+
+	cmdSendMessage                 = 100
+	cmdGetMessagesByUser           = 200
+	cmdGetMessagesByUserInit       = 203
+	cmdGetMessagesByConversation   = 301
+	cmdMarkConversationRead        = 604
+	imInboxType                    = 0
+	imContentTypeProtobuf          = "application/x-protobuf"
+	imConversationDirectionHistory = 1
+)
+
+var imSequenceID atomic.Int64
+
+// imRequest 对应 IM 的 Request 消息
+func imRequest(cmd int, body []byte) []byte {
+	var b []byte
+	b = pb.AppendVarint(b, 1, uint64(cmd))
+	b = pb.AppendVarint(b, 2, uint64(imSequenceID.Add(1)))
+	b = pb.AppendString(b, 3, imSDKVersion)
+	b = pb.AppendVarint(b, 5, 3) // refer: web
+	b = pb.AppendVarint(b, 6, imInboxType)
+	b = pb.AppendString(b, 7, imBuildNumber)
+	b = pb.AppendBytes(b, 8, pb.AppendBytes(nil, protowire.Number(cmd), body))
+	b = pb.AppendString(b, 9, "0")
+	b = pb.AppendString(b, 11, "web")
+	b = appendStringMap(b, 15, map[string]string{
+		"session_aid":     "6383",
+		"session_did":     "0",
+		"app_name":        "douyin_pc",
+		"priority_region": "cn",
+	})
+	b = pb.AppendVarint(b, 18, 1) // auth_type: cookie
+	return b
+}
+
+// appendStringMap 追加 map<string, string> 字段
+func appendStringMap(b []byte, num protowire.Number, m map[string]string) []byte {
+	for k, v := range m {
+		var entry []byte
+		entry = pb.AppendString(entry, 1, k)
+		entry = pb.AppendString(entry, 2, v)
+		b = pb.AppendBytes(b, num, entry)
+	}
+	return b
+}
+
+// imResponse 对应 IM 的 Response 消息
+type imResponse struct {
+	Cmd        int
+	StatusCode int
+	ErrorDesc  string
+	Body       []byte
+}
+
+func parseIMResponse(b []byte) (*imResponse, error) {
+	r := &imResponse{}
+	err := pb.Walk(b, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			r.Cmd = int(f.Varint)
+		case 3:
+			r.StatusCode = int(f.Varint)
+		case 4:
+			r.ErrorDesc = f.String()
+		case 6:
+			// ResponseBody 中只有与 cmd 对应的一个字段
+			return pb.Walk(f.Bytes, func(bf pb.Field) error {
+				r.Body = bf.Bytes
+				return nil
+			})
+		}
+		return nil
+	})
+	return r, err
+}
+
+// imCall 发送一次 IM 请求，返回与 cmd 对应的响应体
+func (c *DyClient) imCall(ctx context.Context, path string, cmd int, body []byte) ([]byte, error) {
+	resp, err := c.imClient.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		SetHeaders(map[string]string{
+			"Content-Type": imContentTypeProtobuf,
+			"Accept":       imContentTypeProtobuf,
+			"Origin":       douyinWebURL,
+			"Referer":      douyinWebURL + "/",
+		}).
+		SetBody(bytes.NewReader(imRequest(cmd, body))).
+		Post(imAPIURL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("im request %s failed, http status: %d", path, resp.StatusCode())
+	}
+	r, err := parseIMResponse(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode im response failed: %w", err)
+	}
+	if r.StatusCode != 0 {
+		return nil, fmt.Errorf("im request %s failed, status code: %d, msg: %s", path, r.StatusCode, r.ErrorDesc)
+	}
+	return r.Body, nil
+}
+
+// parseMessageBody 解析 MessageBody
+func parseMessageBody(b []byte) (DirectMessage, error) {
+	var m DirectMessage
+	err := pb.Walk(b, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			m.ConversationID = f.String()
+		case 2:
+			m.ConversationType = int(f.Varint)
+		case 3:
+			m.ConversationShortID = f.Int64()
+		case 4:
+			m.ServerMessageID = f.Int64()
+		case 5:
+			m.Index = f.Int64()
+		case 8:
+			m.Content = f.String()
+		case 10:
+			m.Type = int(f.Varint)
+		case 11:
+			m.Sender = f.Int64()
+		case 12:
+			m.CreateTime = time.UnixMilli(f.Int64())
+		case 14:
+			m.SecSender = f.String()
+		}
+		return nil
+	})
+	return m, err
+}
+
+// parseConversation 解析 ConversationInfoV2
+func parseConversation(b []byte) (Conversation, error) {
+	var conv Conversation
+	err := pb.Walk(b, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			conv.ID = f.String()
+		case 2:
+			conv.ShortID = f.Int64()
+		case 3:
+			conv.Type = int(f.Varint)
+		case 4:
+			conv.Ticket = f.String()
+		case 6:
+			return pb.Walk(f.Bytes, func(pf pb.Field) error {
+				if pf.Num != 1 {
+					return nil
+				}
+				var p Participant
+				err := pb.Walk(pf.Bytes, func(uf pb.Field) error {
+					switch uf.Num {
+					case 1:
+						p.UserID = uf.Int64()
+					case 5:
+						p.SecUID = uf.String()
+					}
+					return nil
+				})
+				conv.Participants = append(conv.Participants, p)
+				return err
+			})
+		case 9:
+			conv.Unread = f.Int64()
+		}
+		return nil
+	})
+	return conv, err
+}
+
+// conversationRef 请求体中标识会话的公共字段
+func conversationRef(conv *Conversation) []byte {
+	var b []byte
+	b = pb.AppendString(b, 1, conv.ID)
+	b = pb.AppendVarint(b, 2, uint64(conv.Type))
+	b = pb.AppendVarint(b, 3, uint64(conv.ShortID))
+	return b
+}
@@ -0,0 +1,205 @@
+package douyin
+
+import (
+	"context"
+	"crawler-sdk/internal/pb"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testMessageBody(convID string, index int64, text string) []byte {
+	var b []byte
+	b = pb.AppendString(b, 1, convID)
+	b = pb.AppendVarint(b, 2, ConversationOneToOne)
+	b = pb.AppendVarint(b, 3, 7001)
+	b = pb.AppendVarint(b, 4, 9001)
+	b = pb.AppendVarint(b, 5, uint64(index))
+	b = pb.AppendString(b, 8, `{"text":"`+text+`","aweType":700}`)
+	b = pb.AppendVarint(b, 10, DMTypeText)
+	b = pb.AppendVarint(b, 11, 123)
+	b = pb.AppendVarint(b, 12, 1700000000000)
+	b = pb.AppendString(b, 14, "MS4wsender")
+	b = pb.AppendString(b, 99, "unknown field")
+	return b
+}
+
+func TestParseMessageBody(t *testing.T) {
+	m, err := parseMessageBody(testMessageBody("0:1:123:456", 42, "你好"))
+	if err != nil {
+		t.Fatalf("parseMessageBody() error = %v", err)
+	}
+	want := DirectMessage{
+		ConversationID:      "0:1:123:456",
+		ConversationShortID: 7001,
+		ConversationType:    ConversationOneToOne,
+		ServerMessageID:     9001,
+		Index:               42,
+		Sender:              123,
+		SecSender:           "MS4wsender",
+		Type:                DMTypeText,
+		Content:             `{"text":"你好","aweType":700}`,
+		CreateTime:          time.UnixMilli(1700000000000),
+	}
+	if m != want {
+		t.Errorf("parseMessageBody() = %+v, want %+v", m, want)
+	}
+	if m.Text() != "你好" {
+		t.Errorf("Text() = %q", m.Text())
+	}
+}
+
+func TestParseMessageBodyTruncated(t *testing.T) {
+	b := testMessageBody("0:1:123:456", 1, "hi")
+	if _, err := parseMessageBody(b[:len(b)-3]); err == nil {
+		t.Error("parseMessageBody() expected error for truncated input")
+	}
+}
+
+func TestParseConversation(t *testing.T) {
+	participant := func(uid uint64, secUID string) []byte {
+		var p []byte
+		p = pb.AppendVarint(p, 1, uid)
+		p = pb.AppendString(p, 5, secUID)
+		return pb.AppendBytes(nil, 1, p)
+	}
+	var members []byte
+	members = append(members, participant(123, "MS4wa")...)
+	members = append(members, participant(456, "MS4wb")...)
+	members = pb.AppendVarint(members, 2, 2) // 非成员字段应被忽略
+
+	var b []byte
+	b = pb.AppendString(b, 1, "0:1:123:456")
+	b = pb.AppendVarint(b, 2, 7001)
+	b = pb.AppendVarint(b, 3, ConversationOneToOne)
+	b = pb.AppendString(b, 4, "ticket")
+	b = pb.AppendBytes(b, 6, members)
+	b = pb.AppendVarint(b, 9, 3)
+
+	conv, err := parseConversation(b)
+	if err != nil {
+		t.Fatalf("parseConversation() error = %v", err)
+	}
+	if conv.ID != "0:1:123:456" || conv.ShortID != 7001 || conv.Type != ConversationOneToOne || conv.Ticket != "ticket" || conv.Unread != 3 {
+		t.Errorf("parseConversation() = %+v", conv)
+	}
+	want := []Participant{{123, "MS4wa"}, {456, "MS4wb"}}
+	if len(conv.Participants) != len(want) || conv.Participants[0] != want[0] || conv.Participants[1] != want[1] {
+		t.Errorf("Participants = %+v, want %+v", conv.Participants, want)
+	}
+}
+
+func TestConversationRef(t *testing.T) {
+	conv := &Conversation{ID: "0:1:123:456", ShortID: 7001, Type: ConversationGroup}
+	got := map[int]any{}
+	err := pb.Walk(conversationRef(conv), func(f pb.Field) error {
+		if f.Num == 1 {
+			got[int(f.Num)] = f.String()
+		} else {
+			got[int(f.Num)] = f.Int64()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[1] != "0:1:123:456" || got[2] != int64(ConversationGroup) || got[3] != int64(7001) || len(got) != 3 {
+		t.Errorf("conversationRef() fields = %v", got)
+	}
+}
+
+func TestAppendStringMap(t *testing.T) {
+	in := map[string]string{"a": "1", "b": "", "中文": "值"}
+	got := map[string]string{}
+	err := pb.Walk(appendStringMap(nil, 5, in), func(f pb.Field) error {
+		if f.Num != 5 {
+			t.Errorf("field number = %d, want 5", f.Num)
+		}
+		var k, v string
+		err := pb.Walk(f.Bytes, func(ef pb.Field) error {
+			switch ef.Num {
+			case 1:
+				k = ef.String()
+			case 2:
+				v = ef.String()
+			}
+			return nil
+		})
+		got[k] = v
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(in) || got["a"] != "1" || got["b"] != "" || got["中文"] != "值" {
+		t.Errorf("appendStringMap() decoded = %v, want %v", got, in)
+	}
+}
+
+func TestParseIMResponse(t *testing.T) {
+	body := pb.AppendBytes(nil, cmdGetMessagesByUser, []byte("inner"))
+	var b []byte
+	b = pb.AppendVarint(b, 1, cmdGetMessagesByUser)
+	b = pb.AppendVarint(b, 3, 0)
+	b = pb.AppendString(b, 4, "OK")
+	b = pb.AppendBytes(b, 6, body)
+
+	r, err := parseIMResponse(b)
+	if err != nil {
+		t.Fatalf("parseIMResponse() error = %v", err)
+	}
+	if r.Cmd != cmdGetMessagesByUser || r.StatusCode != 0 || r.ErrorDesc != "OK" || string(r.Body) != "inner" {
+		t.Errorf("parseIMResponse() = %+v", r)
+	}
+}
+
+func TestDrainInbox(t *testing.T) {
+	type page struct {
+		next    int64
+		hasMore bool
+	}
+	tests := []struct {
+		name       string
+		pages      map[int64]page
+		wantCursor int64
+		wantCalls  int
+	}{
+		{"single page", map[int64]page{10: {11, false}}, 11, 1},
+		{"follows has_more", map[int64]page{10: {11, true}, 11: {12, true}, 12: {13, false}}, 13, 3},
+		{"cursor does not advance", map[int64]page{10: {10, true}}, 10, 1},
+		{"stalls after advancing", map[int64]page{10: {11, true}, 11: {11, true}}, 11, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			fetch := func(ctx context.Context, cursor int64) ([]DirectMessage, int64, bool, error) {
+				calls++
+				if calls > 10 {
+					t.Fatal("drainInbox() keeps polling")
+				}
+				p := tt.pages[cursor]
+				return []DirectMessage{{Index: cursor}}, p.next, p.hasMore, nil
+			}
+			out := make(chan DirectMessage, 16)
+			cursor, err := drainInbox(context.Background(), 10, fetch, out)
+			if err != nil {
+				t.Fatalf("drainInbox() error = %v", err)
+			}
+			if cursor != tt.wantCursor || calls != tt.wantCalls || len(out) != tt.wantCalls {
+				t.Errorf("drainInbox() cursor = %d after %d calls and %d messages, want %d after %d",
+					cursor, calls, len(out), tt.wantCursor, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDrainInboxError(t *testing.T) {
+	errIM := errors.New("im failed")
+	fetch := func(ctx context.Context, cursor int64) ([]DirectMessage, int64, bool, error) {
+		return nil, 0, false, errIM
+	}
+	cursor, err := drainInbox(context.Background(), 10, fetch, make(chan DirectMessage))
+	if !errors.Is(err, errIM) || cursor != 10 {
+		t.Errorf("drainInbox() = %d, %v, want 10, %v", cursor, err, errIM)
+	}
+}
@@ -0,0 +1,207 @@
+// Package live 抖音直播间信息与弹幕 (WebSocket 推送) 客户端
+package live
+
+import (
+	"context"
+	"crawler-sdk/internal/crypto/dy/ab"
+	"crawler-sdk/pkg/http"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"resty.dev/v3"
+)
+
+const liveWebURL = "https://live.douyin.com"
+
+// RoomStatus 直播间状态
+type RoomStatus int
+
+const (
+	// RoomLiving 正在直播
+	RoomLiving RoomStatus = 2
+	// RoomEnded 直播已结束
+	RoomEnded RoomStatus = 4
+)
+
+// 清晰度，按从高到低排序
+var qualityOrder = []string{"ORIGIN", "FULL_HD1", "HD1", "SD1", "SD2"}
+
+// StreamURL 某一清晰度的拉流地址
+type StreamURL struct {
+	Quality string
+	FLV     string
+	HLS     string
+}
+
+// Anchor 主播信息
+type Anchor struct {
+	ID       string `json:"id_str"`
+	SecUID   string `json:"sec_uid"`
+	Nickname string `json:"nickname"`
+}
+
+// Room 直播间信息
+type Room struct {
+	ID        string
+	WebRID    string
+	Title     string
+	Status    RoomStatus
+	UserCount string
+	Anchor    Anchor
+	// Streams 按清晰度从高到低排列，未开播时为空
+	Streams []StreamURL
+}
+
+// IsLiving 是否正在直播
+func (r *Room) IsLiving() bool {
+	return r.Status == RoomLiving
+}
+
+// Client 抖音直播客户端
+type Client struct {
+	client *resty.Client
+	cookie string
+}
+
+// New 创建直播客户端
+// cookie: 抖音 cookie 字符串，至少需要包含 ttwid
+func New(cookie string) *Client {
+	c := &Client{
+		client: http.NewClient(cookie),
+		cookie: cookie,
+	}
+	c.client.AddRequestMiddleware(absign)
+	return c
+}
+
+func absign(c *resty.Client, req *resty.Request) error {
+	sign, err := ab.SignDetail(req.QueryParams.Encode(), c.Header().Get("User-Agent"))
+	if err != nil {
+		return err
+	}
+	req.SetQueryParam("a_bogus", sign)
+	return nil
+}
+
+type roomEnterResponse struct {
+	StatusCode int `json:"status_code"`
+	Data       struct {
+		Data []struct {
+			IDStr        string     `json:"id_str"`
+			Status       RoomStatus `json:"status"`
+			Title        string     `json:"title"`
+			UserCountStr string     `json:"user_count_str"`
+			StreamURL    struct {
+				FlvPullURL    map[string]string `json:"flv_pull_url"`
+				HlsPullURLMap map[string]string `json:"hls_pull_url_map"`
+				LiveCoreSDK   struct {
+					PullData struct {
+						StreamData string `json:"stream_data"`
+					} `json:"pull_data"`
+				} `json:"live_core_sdk_data"`
+			} `json:"stream_url"`
+		} `json:"data"`
+		User Anchor `json:"user"`
+	} `json:"data"`
+	Message string `json:"message"`
+}
+
+// streamData live_core_sdk_data 中以 JSON 字符串返回的完整拉流信息，包含原画
+type streamData struct {
+	Data map[string]struct {
+		Main struct {
+			FLV string `json:"flv"`
+			HLS string `json:"hls"`
+		} `json:"main"`
+	} `json:"data"`
+}
+
+// GetRoom 根据直播间 web id (live.douyin.com/ 后面的数字) 获取直播间状态与拉流地址
+func (c *Client) GetRoom(ctx context.Context, webRID string) (*Room, error) {
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&roomEnterResponse{}).
+		SetHeader("Referer", liveWebURL+"/"+webRID).
+		SetQueryParams(map[string]string{
+			"aid":                   "6383",
+			"app_name":              "douyin_web",
+			"live_id":               "1",
+			"device_platform":       "web",
+			"language":              "zh-CN",
+			"enter_from":            "web_live",
+			"cookie_enabled":        "true",
+			"screen_width":          "1920",
+			"screen_height":         "1080",
+			"browser_language":      "zh-CN",
+			"browser_platform":      "Win32",
+			"browser_name":          "Edge",
+			"browser_version":       "139.0.0.0",
+			"web_rid":               webRID,
+			"enter_source":          "",
+			"is_need_double_stream": "false",
+		}).
+		Get(liveWebURL + "/webcast/room/web/enter/")
+	if err != nil {
+		return nil, err
+	}
+
+	result := resp.Result().(*roomEnterResponse)
+	if result.StatusCode != 0 {
+		return nil, fmt.Errorf("get live room failed, status code: %d, msg: %s", result.StatusCode, result.Message)
+	}
+	if len(result.Data.Data) == 0 {
+		return nil, fmt.Errorf("live room %s not found", webRID)
+	}
+
+	data := result.Data.Data[0]
+	room := &Room{
+		ID:        data.IDStr,
+		WebRID:    webRID,
+		Title:     data.Title,
+		Status:    data.Status,
+		UserCount: data.UserCountStr,
+		Anchor:    result.Data.User,
+	}
+
+	streams := map[string]*StreamURL{}
+	stream := func(quality string) *StreamURL {
+		if s, ok := streams[quality]; ok {
+			return s
+		}
+		s := &StreamURL{Quality: quality}
+		streams[quality] = s
+		return s
+	}
+	for quality, u := range data.StreamURL.FlvPullURL {
+		stream(quality).FLV = u
+	}
+	for quality, u := range data.StreamURL.HlsPullURLMap {
+		stream(quality).HLS = u
+	}
+	if raw := data.StreamURL.LiveCoreSDK.PullData.StreamData; raw != "" {
+		var sd streamData
+		if err := json.Unmarshal([]byte(raw), &sd); err == nil {
+			if origin, ok := sd.Data["origin"]; ok && origin.Main.FLV != "" {
+				s := stream("ORIGIN")
+				s.FLV = origin.Main.FLV
+				s.HLS = origin.Main.HLS
+			}
+		}
+	}
+	for _, quality := range qualityOrder {
+		if s, ok := streams[quality]; ok {
+			room.Streams = append(room.Streams, *s)
+			delete(streams, quality)
+		}
+	}
+	rest := make([]string, 0, len(streams))
+	for quality := range streams {
+		rest = append(rest, quality)
+	}
+	sort.Strings(rest)
+	for _, quality := range rest {
+		room.Streams = append(room.Streams, *streams[quality])
+	}
+	return room, nil
+}
@@ -0,0 +1,379 @@
+package live
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crawler-sdk/internal/pb"
+	"io"
+)
+
+// pushFrame 推送连接上的一帧 (webcast PushFrame)
+type pushFrame struct {
+	SeqID           uint64
+	LogID           uint64
+	Service         uint64
+	Method          uint64
+	Headers         map[string]string
+	PayloadEncoding string
+	PayloadType     string
+	Payload         []byte
+}
+
+func (f *pushFrame) marshal() []byte {
+	var b []byte
+	b = pb.AppendVarint(b, 1, f.SeqID)
+	b = pb.AppendVarint(b, 2, f.LogID)
+	b = pb.AppendVarint(b, 3, f.Service)
+	b = pb.AppendVarint(b, 4, f.Method)
+	for k, v := range f.Headers {
+		var h []byte
+		h = pb.AppendString(h, 1, k)
+		h = pb.AppendString(h, 2, v)
+		b = pb.AppendBytes(b, 5, h)
+	}
+	b = pb.AppendString(b, 6, f.PayloadEncoding)
+	b = pb.AppendString(b, 7, f.PayloadType)
+	b = pb.AppendBytes(b, 8, f.Payload)
+	return b
+}
+
+func parsePushFrame(b []byte) (*pushFrame, error) {
+	f := &pushFrame{Headers: map[string]string{}}
+	err := pb.Walk(b, func(fd pb.Field) error {
+		switch fd.Num {
+		case 1:
+			f.SeqID = fd.Varint
+		case 2:
+			f.LogID = fd.Varint
+		case 3:
+			f.Service = fd.Varint
+		case 4:
+			f.Method = fd.Varint
+		case 5:
+			var k, v string
+			err := pb.Walk(fd.Bytes, func(h pb.Field) error {
+				switch h.Num {
+				case 1:
+					k = h.String()
+				case 2:
+					v = h.String()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			f.Headers[k] = v
+		case 6:
+			f.PayloadEncoding = fd.String()
+		case 7:
+			f.PayloadType = fd.String()
+		case 8:
+			f.Payload = fd.Bytes
+		}
+		return nil
+	})
+	return f, err
+}
+
+// body 返回解压后的 payload
+func (f *pushFrame) body() ([]byte, error) {
+	if f.Headers["compress_type"] != "gzip" && f.PayloadEncoding != "gzip" {
+		return f.Payload, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(f.Payload))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// pushMessage Response 中的单条消息
+type pushMessage struct {
+	Method  string
+	Payload []byte
+	MsgID   int64
+}
+
+// pushResponse 帧 payload 解压后的 webcast Response
+type pushResponse struct {
+	Messages    []pushMessage
+	Cursor      string
+	InternalExt string
+	NeedAck     bool
+}
+
+func parsePushResponse(b []byte) (*pushResponse, error) {
+	r := &pushResponse{}
+	err := pb.Walk(b, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			var m pushMessage
+			err := pb.Walk(f.Bytes, func(mf pb.Field) error {
+				switch mf.Num {
+				case 1:
+					m.Method = mf.String()
+				case 2:
+					m.Payload = mf.Bytes
+				case 3:
+					m.MsgID = mf.Int64()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			r.Messages = append(r.Messages, m)
+		case 2:
+			r.Cursor = f.String()
+		case 5:
+			r.InternalExt = f.String()
+		case 9:
+			r.NeedAck = f.Bool()
+		}
+		return nil
+	})
+	return r, err
+}
+
+// Event 直播间事件，具体类型为 *ChatEvent、*GiftEvent、*LikeEvent、
+// *MemberEvent、*RoomStatsEvent、*ControlEvent 或 *RawEvent
+type Event interface {
+	common() *Common
+}
+
+// Common 所有事件共有的字段
+type Common struct {
+	Method     string
+	MsgID      uint64
+	RoomID     uint64
+	CreateTime int64
+}
+
+func (c *Common) common() *Common {
+	return c
+}
+
+// User 事件中的用户
+type User struct {
+	ID        uint64
+	Nickname  string
+	DisplayID string
+	SecUID    string
+}
+
+// ChatEvent 弹幕
+type ChatEvent struct {
+	Common
+	User    User
+	Content string
+}
+
+// GiftEvent 礼物
+type GiftEvent struct {
+	Common
+	User         User
+	GiftID       uint64
+	GiftName     string
+	DiamondCount int64
+	GroupCount   int64
+	RepeatCount  int64
+	ComboCount   int64
+	// RepeatEnd 连击结束，统计礼物数量时应只计算 RepeatEnd 的事件
+	RepeatEnd bool
+}
+
+// LikeEvent 点赞
+type LikeEvent struct {
+	Common
+	User  User
+	Count int64
+	Total int64
+}
+
+// MemberEvent 用户进入直播间
+type MemberEvent struct {
+	Common
+	User        User
+	MemberCount int64
+}
+
+// RoomStatsEvent 直播间在线人数等统计
+type RoomStatsEvent struct {
+	Common
+	Online       int64
+	OnlineStr    string
+	TotalUser    int64
+	TotalUserStr string
+}
+
+// ControlEvent 直播间控制消息
+type ControlEvent struct {
+	Common
+	Status int64
+}
+
+// controlStatusEnded ControlEvent 中表示直播结束的状态
+const controlStatusEnded = 3
+
+// Ended 直播是否已结束
+func (e *ControlEvent) Ended() bool {
+	return e.Status == controlStatusEnded
+}
+
+// RawEvent 未解析的其他消息
+type RawEvent struct {
+	Common
+	Payload []byte
+}
+
+func parseCommon(b []byte, c *Common) error {
+	return pb.Walk(b, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			c.Method = f.String()
+		case 2:
+			c.MsgID = f.Varint
+		case 3:
+			c.RoomID = f.Varint
+		case 4:
+			c.CreateTime = f.Int64()
+		}
+		return nil
+	})
+}
+
+func parseUser(b []byte, u *User) error {
+	return pb.Walk(b, func(f pb.Field) error {
+		switch f.Num {
+		case 1:
+			u.ID = f.Varint
+		case 3:
+			u.Nickname = f.String()
+		case 38:
+			u.DisplayID = f.String()
+		case 46:
+			u.SecUID = f.String()
+		}
+		return nil
+	})
+}
+
+// parseEvent 按消息类型解码为事件
+func parseEvent(m pushMessage) (Event, error) {
+	var (
+		e     Event
+		field func(f pb.Field) error
+	)
+	switch m.Method {
+	case "WebcastChatMessage":
+		ev := &ChatEvent{}
+		e, field = ev, func(f pb.Field) error {
+			switch f.Num {
+			case 2:
+				return parseUser(f.Bytes, &ev.User)
+			case 3:
+				ev.Content = f.String()
+			}
+			return nil
+		}
+	case "WebcastGiftMessage":
+		ev := &GiftEvent{}
+		e, field = ev, func(f pb.Field) error {
+			switch f.Num {
+			case 2:
+				ev.GiftID = f.Varint
+			case 4:
+				ev.GroupCount = f.Int64()
+			case 5:
+				ev.RepeatCount = f.Int64()
+			case 6:
+				ev.ComboCount = f.Int64()
+			case 7:
+				return parseUser(f.Bytes, &ev.User)
+			case 9:
+				ev.RepeatEnd = f.Bool()
+			case 15:
+				return pb.Walk(f.Bytes, func(g pb.Field) error {
+					switch g.Num {
+					case 12:
+						ev.DiamondCount = g.Int64()
+					case 16:
+						ev.GiftName = g.String()
+					}
+					return nil
+				})
+			}
+			return nil
+		}
+	case "WebcastLikeMessage":
+		ev := &LikeEvent{}
+		e, field = ev, func(f pb.Field) error {
+			switch f.Num {
+			case 2:
+				ev.Count = f.Int64()
+			case 3:
+				ev.Total = f.Int64()
+			case 5:
+				return parseUser(f.Bytes, &ev.User)
+			}
+			return nil
+		}
+	case "WebcastMemberMessage":
+		ev := &MemberEvent{}
+		e, field = ev, func(f pb.Field) error {
+			switch f.Num {
+			case 2:
+				return parseUser(f.Bytes, &ev.User)
+			case 3:
+				ev.MemberCount = f.Int64()
+			}
+			return nil
+		}
+	case "WebcastRoomUserSeqMessage":
+		ev := &RoomStatsEvent{}
+		e, field = ev, func(f pb.Field) error {
+			switch f.Num {
+			case 3:
+				ev.Online = f.Int64()
+			case 4:
+				ev.OnlineStr = f.String()
+			case 7:
+				ev.TotalUser = f.Int64()
+			case 8:
+				ev.TotalUserStr = f.String()
+			}
+			return nil
+		}
+	case "WebcastControlMessage":
+		ev := &ControlEvent{}
+		e, field = ev, func(f pb.Field) error {
+			if f.Num == 2 {
+				ev.Status = f.Int64()
+			}
+			return nil
+		}
+	default:
+		ev := &RawEvent{Payload: m.Payload}
+		ev.Method = m.Method
+		ev.MsgID = uint64(m.MsgID)
+		return ev, nil
+	}
+
+	c := e.common()
+	err := pb.Walk(m.Payload, func(f pb.Field) error {
+		if f.Num == 1 {
+			return parseCommon(f.Bytes, c)
+		}
+		return field(f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.Method = m.Method
+	if c.MsgID == 0 {
+		c.MsgID = uint64(m.MsgID)
+	}
+	return e, nil
+}
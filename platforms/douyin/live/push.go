@@ -0,0 +1,337 @@
+package live
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const defaultPushURL = "wss://webcast5-ws-web-lf.douyin.com/webcast/im/push/v2/"
+
+// SignFunc 计算推送连接的 signature 参数
+// stub 为连接参数的 MD5 (X-MS-STUB)，网页端由 byted_acrawler.frontierSign 计算签名，
+// 需由调用方在浏览器环境或签名服务中实现
+type SignFunc func(stub string) (string, error)
+
+// WatchOptions 弹幕连接选项
+type WatchOptions struct {
+	// PushURL 推送服务地址，默认为抖音网页端地址
+	PushURL string
+	// Sign 计算 signature 参数，为空时不携带签名
+	Sign SignFunc
+	// HeartbeatInterval 心跳间隔，默认 10 秒
+	HeartbeatInterval time.Duration
+	// ReconnectDelay 断线后重连前的等待时间，默认 3 秒
+	ReconnectDelay time.Duration
+	// MaxRetries 连续重连失败的最大次数，默认 5 次，小于 0 时不重连
+	MaxRetries int
+	// Buffer 事件通道的缓冲大小，默认 256
+	Buffer int
+}
+
+func (o *WatchOptions) withDefaults() WatchOptions {
+	opts := WatchOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.PushURL == "" {
+		opts.PushURL = defaultPushURL
+	}
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = 10 * time.Second
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = 3 * time.Second
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 5
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 256
+	}
+	return opts
+}
+
+// Watcher 直播间弹幕连接
+//
+//	w, err := client.Watch(ctx, room.ID, nil)
+//	for ev := range w.Events() {
+//		switch e := ev.(type) {
+//		case *live.ChatEvent:
+//			...
+//		}
+//	}
+//	err = w.Err()
+type Watcher struct {
+	c      *Client
+	roomID string
+	opts   WatchOptions
+	uid    string
+
+	events chan Event
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Watch 连接直播间的推送服务，事件在 Events 通道中返回
+// 连接断开后会自动重连；ctx 取消、调用 Close、直播结束或重连次数耗尽时通道关闭
+func (c *Client) Watch(ctx context.Context, roomID string, opts *WatchOptions) (*Watcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher{
+		c:      c,
+		roomID: roomID,
+		opts:   opts.withDefaults(),
+		uid:    strconv.FormatInt(7300000000000000000+rand.Int63n(1e17), 10),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	w.events = make(chan Event, w.opts.Buffer)
+
+	conn, err := w.dial(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go w.run(ctx, conn)
+	return w, nil
+}
+
+// Events 事件通道
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Err 在事件通道关闭后返回导致连接结束的错误，正常结束时为 nil
+func (w *Watcher) Err() error {
+	<-w.done
+	return w.err
+}
+
+// Close 断开连接并等待事件通道关闭
+func (w *Watcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+// errRoomEnded 直播结束，连接正常结束
+var errRoomEnded = errors.New("live room ended")
+
+func (w *Watcher) run(ctx context.Context, conn *websocket.Conn) {
+	defer close(w.done)
+	defer close(w.events)
+	defer w.cancel()
+
+	retries := 0
+	for {
+		received, err := w.serve(ctx, conn)
+		if errors.Is(err, errRoomEnded) || ctx.Err() != nil {
+			return
+		}
+		if received {
+			retries = 0
+		}
+
+		for {
+			retries++
+			if w.opts.MaxRetries < 0 || retries > w.opts.MaxRetries {
+				w.err = fmt.Errorf("live push connection lost: %w", err)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.opts.ReconnectDelay):
+			}
+			conn, err = w.dial(ctx)
+			if err == nil {
+				break
+			}
+		}
+	}
+}
+
+// serve 读取一个连接上的帧直到连接断开，received 表示是否收到过消息
+func (w *Watcher) serve(ctx context.Context, conn *websocket.Conn) (received bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	write := func(f *pushFrame) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return conn.WriteMessage(websocket.BinaryMessage, f.marshal())
+	}
+
+	go func() {
+		ticker := time.NewTicker(w.opts.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				conn.Close()
+				return
+			case <-ticker.C:
+				if write(&pushFrame{PayloadType: "hb"}) != nil {
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return received, err
+		}
+		frame, err := parsePushFrame(data)
+		if err != nil {
+			return received, err
+		}
+		if frame.PayloadType != "msg" {
+			continue
+		}
+		body, err := frame.body()
+		if err != nil {
+			return received, err
+		}
+		resp, err := parsePushResponse(body)
+		if err != nil {
+			return received, err
+		}
+		received = true
+
+		if resp.NeedAck {
+			ack := &pushFrame{LogID: frame.LogID, PayloadType: "ack", Payload: []byte(resp.InternalExt)}
+			if err := write(ack); err != nil {
+				return received, err
+			}
+		}
+
+		for _, m := range resp.Messages {
+			ev, err := parseEvent(m)
+			if err != nil {
+				continue
+			}
+			select {
+			case w.events <- ev:
+			case <-ctx.Done():
+				return received, ctx.Err()
+			}
+			if ce, ok := ev.(*ControlEvent); ok && ce.Ended() {
+				return received, errRoomEnded
+			}
+		}
+	}
+}
+
+// dial 建立推送连接
+func (w *Watcher) dial(ctx context.Context) (*websocket.Conn, error) {
+	u, err := w.pushURL()
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("User-Agent", w.c.client.Header().Get("User-Agent"))
+	header.Set("Origin", liveWebURL)
+	if w.c.cookie != "" {
+		header.Set("Cookie", w.c.cookie)
+	}
+
+	dialer := w.c.pushDialer()
+	conn, resp, err := dialer.DialContext(ctx, u, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("dial live push failed, http status: %d: %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("dial live push failed: %w", err)
+	}
+	return conn, nil
+}
+
+// pushDialer 返回推送连接使用的 dialer，沿用 HTTP 客户端的代理，使推送连接与接口请求从同一出口发出
+func (c *Client) pushDialer() websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	if transport, err := c.client.HTTPTransport(); err == nil {
+		// 客户端未设置代理时直连，而不是回落到环境变量中的代理
+		dialer.Proxy = transport.Proxy
+	}
+	return dialer
+}
+
+// pushURL 拼接推送连接地址
+func (w *Watcher) pushURL() (string, error) {
+	u, err := url.Parse(w.opts.PushURL)
+	if err != nil {
+		return "", err
+	}
+	const sdkVersion = "1.0.14-beta.0"
+	q := url.Values{}
+	q.Set("app_name", "douyin_web")
+	q.Set("version_code", "180800")
+	q.Set("webcast_sdk_version", sdkVersion)
+	q.Set("update_version_code", sdkVersion)
+	q.Set("compress", "gzip")
+	q.Set("device_platform", "web")
+	q.Set("cookie_enabled", "true")
+	q.Set("browser_language", "zh-CN")
+	q.Set("browser_platform", "Win32")
+	q.Set("browser_name", "Mozilla")
+	q.Set("browser_online", "true")
+	q.Set("tz_name", "Asia/Shanghai")
+	q.Set("host", liveWebURL)
+	q.Set("aid", "6383")
+	q.Set("live_id", "1")
+	q.Set("did_rule", "3")
+	q.Set("endpoint", "live_pc")
+	q.Set("support_wrds", "1")
+	q.Set("user_unique_id", w.uid)
+	q.Set("im_path", "/webcast/im/fetch/")
+	q.Set("identity", "audience")
+	q.Set("need_persist_msg_count", "15")
+	q.Set("room_id", w.roomID)
+	q.Set("heartbeatDuration", "0")
+
+	if w.opts.Sign != nil {
+		signature, err := w.opts.Sign(w.stub())
+		if err != nil {
+			return "", fmt.Errorf("sign live push url failed: %w", err)
+		}
+		q.Set("signature", signature)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// stub 网页端签名所用的参数摘要
+func (w *Watcher) stub() string {
+	params := []string{
+		"live_id=1",
+		"aid=6383",
+		"version_code=180800",
+		"webcast_sdk_version=1.0.14-beta.0",
+		"room_id=" + w.roomID,
+		"sub_room_id=",
+		"sub_channel_id=",
+		"did_rule=3",
+		"user_unique_id=" + w.uid,
+		"device_platform=web",
+		"device_type=",
+		"ac=",
+		"identity=audience",
+	}
+	sum := md5.Sum([]byte(strings.Join(params, ",")))
+	return hex.EncodeToString(sum[:])
+}
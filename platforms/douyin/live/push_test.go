@@ -0,0 +1,216 @@
+package live
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crawler-sdk/internal/pb"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func testUser(id uint64, nickname string) []byte {
+	var b []byte
+	b = pb.AppendVarint(b, 1, id)
+	b = pb.AppendString(b, 3, nickname)
+	return b
+}
+
+func testMessage(method string, fields []byte) []byte {
+	var common []byte
+	common = pb.AppendString(common, 1, method)
+	common = pb.AppendVarint(common, 2, 1001)
+	common = pb.AppendVarint(common, 3, 42)
+
+	var payload []byte
+	payload = pb.AppendBytes(payload, 1, common)
+	payload = append(payload, fields...)
+
+	var m []byte
+	m = pb.AppendString(m, 1, method)
+	m = pb.AppendBytes(m, 2, payload)
+	return m
+}
+
+func testFrame(t *testing.T, needAck bool, messages ...[]byte) []byte {
+	var resp []byte
+	for _, m := range messages {
+		resp = pb.AppendBytes(resp, 1, m)
+	}
+	resp = pb.AppendString(resp, 5, "internal-ext")
+	if needAck {
+		resp = pb.AppendVarint(resp, 9, 1)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(resp); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+
+	f := &pushFrame{
+		LogID:       7,
+		Headers:     map[string]string{"compress_type": "gzip"},
+		PayloadType: "msg",
+		Payload:     buf.Bytes(),
+	}
+	return f.marshal()
+}
+
+func TestWatch(t *testing.T) {
+	var (
+		conns int32
+		acked int32
+	)
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("room_id") != "42" {
+			http.Error(w, "bad room", http.StatusBadRequest)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		switch atomic.AddInt32(&conns, 1) {
+		case 1:
+			var chat, gift, like, member, stats []byte
+			chat = append(pb.AppendBytes(chat, 2, testUser(1, "alice")), pb.AppendString(nil, 3, "hello")...)
+
+			var giftInfo []byte
+			giftInfo = pb.AppendVarint(giftInfo, 12, 1)
+			giftInfo = pb.AppendString(giftInfo, 16, "rose")
+			gift = pb.AppendVarint(gift, 2, 463)
+			gift = pb.AppendVarint(gift, 5, 3)
+			gift = pb.AppendBytes(gift, 7, testUser(2, "bob"))
+			gift = pb.AppendVarint(gift, 9, 1)
+			gift = pb.AppendBytes(gift, 15, giftInfo)
+
+			like = pb.AppendVarint(like, 2, 5)
+			like = pb.AppendVarint(like, 3, 100)
+			like = pb.AppendBytes(like, 5, testUser(3, "carol"))
+
+			member = pb.AppendBytes(member, 2, testUser(4, "dave"))
+			member = pb.AppendVarint(member, 3, 10)
+
+			stats = pb.AppendVarint(stats, 3, 321)
+			stats = pb.AppendString(stats, 8, "1.2万")
+
+			conn.WriteMessage(websocket.BinaryMessage, testFrame(t, true,
+				testMessage("WebcastChatMessage", chat),
+				testMessage("WebcastGiftMessage", gift),
+				testMessage("WebcastLikeMessage", like),
+				testMessage("WebcastMemberMessage", member),
+				testMessage("WebcastRoomUserSeqMessage", stats),
+			))
+
+			// 等待 ack 后断开，触发客户端重连
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				f, err := parsePushFrame(data)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if f.PayloadType == "ack" {
+					if f.LogID != 7 || string(f.Payload) != "internal-ext" {
+						t.Errorf("unexpected ack frame: %+v", f)
+					}
+					atomic.AddInt32(&acked, 1)
+					return
+				}
+			}
+		default:
+			control := pb.AppendVarint(nil, 2, controlStatusEnded)
+			conn.WriteMessage(websocket.BinaryMessage, testFrame(t, false, testMessage("WebcastControlMessage", control)))
+			conn.ReadMessage()
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := New("ttwid=test")
+	c.client.RemoveProxy()
+	w, err := c.Watch(ctx, "42", &WatchOptions{
+		PushURL:        "ws" + strings.TrimPrefix(srv.URL, "http"),
+		ReconnectDelay: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []Event
+	for ev := range w.Events() {
+		events = append(events, ev)
+	}
+	if err := w.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&conns); got != 2 {
+		t.Errorf("expected 2 connections, got %d", got)
+	}
+	if got := atomic.LoadInt32(&acked); got != 1 {
+		t.Errorf("expected 1 ack, got %d", got)
+	}
+	if len(events) != 6 {
+		t.Fatalf("expected 6 events, got %d", len(events))
+	}
+
+	chat, ok := events[0].(*ChatEvent)
+	if !ok || chat.Content != "hello" || chat.User.Nickname != "alice" || chat.RoomID != 42 {
+		t.Errorf("unexpected chat event: %+v", events[0])
+	}
+	gift, ok := events[1].(*GiftEvent)
+	if !ok || gift.GiftName != "rose" || gift.RepeatCount != 3 || !gift.RepeatEnd || gift.User.ID != 2 {
+		t.Errorf("unexpected gift event: %+v", events[1])
+	}
+	like, ok := events[2].(*LikeEvent)
+	if !ok || like.Count != 5 || like.Total != 100 {
+		t.Errorf("unexpected like event: %+v", events[2])
+	}
+	member, ok := events[3].(*MemberEvent)
+	if !ok || member.User.Nickname != "dave" || member.MemberCount != 10 {
+		t.Errorf("unexpected member event: %+v", events[3])
+	}
+	stats, ok := events[4].(*RoomStatsEvent)
+	if !ok || stats.Online != 321 || stats.TotalUserStr != "1.2万" {
+		t.Errorf("unexpected room stats event: %+v", events[4])
+	}
+	control, ok := events[5].(*ControlEvent)
+	if !ok || !control.Ended() {
+		t.Errorf("unexpected control event: %+v", events[5])
+	}
+}
+
+func TestPushDialerUsesClientProxy(t *testing.T) {
+	c := New("ttwid=test")
+	c.client.SetProxy("http://127.0.0.1:3128")
+	dialer := c.pushDialer()
+	if dialer.Proxy == nil {
+		t.Fatal("pushDialer() has no proxy")
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://webcast5-ws-web-lf.douyin.com/webcast/im/push/v2/", nil)
+	u, err := dialer.Proxy(req)
+	if err != nil || u == nil || u.Host != "127.0.0.1:3128" {
+		t.Errorf("dialer.Proxy() = %v, %v, want 127.0.0.1:3128", u, err)
+	}
+
+	c.client.RemoveProxy()
+	if dialer := c.pushDialer(); dialer.Proxy != nil {
+		t.Error("pushDialer() should not set a proxy when the client has none")
+	}
+}
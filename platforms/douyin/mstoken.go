@@ -0,0 +1,237 @@
+package douyin
+
+import (
+	"context"
+	"crawler-sdk/pkg/http"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"resty.dev/v3"
+)
+
+const (
+	msSDKReportURL = "https://mssdk.bytedance.com/web/report"
+	// msTokenTTL 服务端未返回有效期时 msToken 的缓存时长
+	msTokenTTL = 30 * time.Minute
+	// msTokenRefreshAhead 在过期前提前刷新的时间
+	msTokenRefreshAhead = time.Minute
+	// randomMsTokenTTL 随机 msToken 的缓存时长，过期后重新尝试获取真实 msToken
+	randomMsTokenTTL = 5 * time.Minute
+	// msTokenFetchTimeout 单次上报获取 msToken 的超时时间
+	msTokenFetchTimeout = 15 * time.Second
+)
+
+// Option 客户端选项
+type Option func(*DyClient)
+
+// WithRandomMsToken 在 msToken 获取失败时使用随机生成的 msToken
+// 随机 msToken 无法通过服务端校验，仅适用于不校验 msToken 的接口
+func WithRandomMsToken() Option {
+	return func(c *DyClient) {
+		c.msToken.randomFallback = true
+	}
+}
+
+// WithMsSDKStrData 设置 mssdk 上报使用的环境指纹密文
+// 可从浏览器中 mssdk.bytedance.com/web/report 请求体的 strData 字段获取，未设置时上报空指纹
+func WithMsSDKStrData(strData string) Option {
+	return func(c *DyClient) {
+		c.msToken.strData = strData
+	}
+}
+
+// WithProxy 为客户端的全部请求设置代理，包括游客注册、msToken 上报与上传
+// proxyURL 形如 http://127.0.0.1:8888 或 socks5://127.0.0.1:1080
+func WithProxy(proxyURL string) Option {
+	return func(c *DyClient) {
+		for _, client := range []*resty.Client{c.client, c.imagexClient, c.vodClient, c.uploadClient, c.imClient, c.msToken.client} {
+			client.SetProxy(proxyURL)
+		}
+	}
+}
+
+// msTokenCache 单个客户端的 msToken 缓存
+type msTokenCache struct {
+	mu             sync.Mutex
+	client         *resty.Client
+	token          string
+	expires        time.Time
+	strData        string
+	randomFallback bool
+	// inflight 正在进行的刷新，并发请求共用同一次上报
+	inflight *msTokenCall
+	// fetchToken 获取新 msToken 的方法，默认为 fetch
+	fetchToken func(ctx context.Context, current string) (string, time.Time, error)
+}
+
+// msTokenCall 一次进行中的 msToken 刷新，done 关闭后 token 与 err 可读
+type msTokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+func newMsTokenCache(cookie string) *msTokenCache {
+	m := &msTokenCache{
+		client: http.NewClient(""),
+	}
+	m.fetchToken = m.fetch
+	// cookie 中已有的 msToken 可直接使用
+	for _, part := range strings.Split(cookie, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && k == "msToken" && v != "" {
+			m.token = v
+			m.expires = time.Now().Add(msTokenTTL)
+		}
+	}
+	return m
+}
+
+// get 返回有效的 msToken，缓存过期时重新获取
+// 上报请求在锁外进行，并发调用等待同一次刷新；ctx 取消时立即返回，不影响正在进行的刷新
+func (m *msTokenCache) get(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	if m.token != "" && time.Now().Add(msTokenRefreshAhead).Before(m.expires) {
+		token := m.token
+		m.mu.Unlock()
+		return token, nil
+	}
+	call := m.inflight
+	if call == nil {
+		call = &msTokenCall{done: make(chan struct{})}
+		m.inflight = call
+		// 刷新结果由所有等待者共享，不随发起者的 ctx 取消
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), msTokenFetchTimeout)
+		go func(current string) {
+			defer cancel()
+			m.refresh(fetchCtx, call, current)
+		}(m.token)
+	}
+	m.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// refresh 获取新的 msToken 并在锁内更新缓存，完成后唤醒等待者
+func (m *msTokenCache) refresh(ctx context.Context, call *msTokenCall, current string) {
+	token, expires, err := m.fetchToken(ctx, current)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	defer close(call.done)
+	m.inflight = nil
+	switch {
+	case err == nil:
+		if expires.IsZero() {
+			expires = time.Now().Add(msTokenTTL)
+		}
+		m.token, m.expires = token, expires
+		call.token = token
+	case m.randomFallback:
+		// 短暂缓存随机值，避免每个请求都重试上报
+		m.token, m.expires = getMsToken(msTokenLength), time.Now().Add(randomMsTokenTTL)
+		call.token = m.token
+	default:
+		call.err = fmt.Errorf("get msToken failed: %w", err)
+	}
+}
+
+// set 使用响应中下发的 msToken 更新缓存
+func (m *msTokenCache) set(token string, expires time.Time) {
+	if token == "" {
+		return
+	}
+	if expires.IsZero() {
+		expires = time.Now().Add(msTokenTTL)
+	}
+	m.mu.Lock()
+	m.token, m.expires = token, expires
+	m.mu.Unlock()
+}
+
+// fetch 通过 mssdk 上报接口获取新的 msToken，msToken 在响应的 Set-Cookie 中下发
+func (m *msTokenCache) fetch(ctx context.Context, current string) (string, time.Time, error) {
+	resp, err := m.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json; charset=utf-8").
+		SetHeader("Origin", douyinWebURL).
+		SetHeader("Referer", douyinWebURL+"/").
+		SetQueryParam("msToken", current).
+		SetBody(map[string]any{
+			"magic":         538969122,
+			"version":       1,
+			"dataType":      8,
+			"strData":       m.strData,
+			"tspFromClient": time.Now().UnixMilli(),
+			"ulr":           0,
+		}).
+		Post(msSDKReportURL)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	token, expires := msTokenFromResponse(resp)
+	if token == "" {
+		return "", time.Time{}, errors.New("mssdk report returned no msToken")
+	}
+	return token, expires, nil
+}
+
+// msTokenFromResponse 从 Set-Cookie 或 x-ms-token 响应头中读取 msToken
+func msTokenFromResponse(resp *resty.Response) (string, time.Time) {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name != "msToken" || cookie.Value == "" {
+			continue
+		}
+		expires := cookie.Expires
+		if cookie.MaxAge > 0 {
+			expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+		value, err := url.QueryUnescape(cookie.Value)
+		if err != nil {
+			value = cookie.Value
+		}
+		return value, expires
+	}
+	if token := resp.Header().Get("X-Ms-Token"); token != "" {
+		return token, time.Time{}
+	}
+	return "", time.Time{}
+}
+
+// msTokenMiddleware 为请求添加 msToken 参数
+func (c *DyClient) msTokenMiddleware(_ *resty.Client, req *resty.Request) error {
+	token, err := c.msToken.get(req.Context())
+	if err != nil {
+		return err
+	}
+	req.SetQueryParam("msToken", token)
+	return nil
+}
+
+// msTokenUpdater 服务端在响应中轮换 msToken 时更新缓存
+func (c *DyClient) msTokenUpdater(_ *resty.Client, resp *resty.Response) error {
+	c.msToken.set(msTokenFromResponse(resp))
+	return nil
+}
+
+const msTokenLength = 107
+
+// getMsToken 生成随机 msToken
+func getMsToken(length int) string {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(b)
+}
@@ -0,0 +1,192 @@
+package douyin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"resty.dev/v3"
+)
+
+func TestMsTokenFromResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     func(h http.Header)
+		want       string
+		wantExpiry bool
+	}{
+		{
+			name: "set-cookie with max-age",
+			header: func(h http.Header) {
+				h.Add("Set-Cookie", "msToken=abc%3D%3D; Max-Age=3600; Path=/")
+			},
+			want:       "abc==",
+			wantExpiry: true,
+		},
+		{
+			name: "set-cookie with expires",
+			header: func(h http.Header) {
+				h.Add("Set-Cookie", "msToken=def; Expires="+time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+			},
+			want:       "def",
+			wantExpiry: true,
+		},
+		{
+			name:   "x-ms-token header",
+			header: func(h http.Header) { h.Set("X-Ms-Token", "ghi") },
+			want:   "ghi",
+		},
+		{
+			name: "empty cookie falls back to header",
+			header: func(h http.Header) {
+				h.Add("Set-Cookie", "msToken=; Path=/")
+				h.Set("X-Ms-Token", "jkl")
+			},
+			want: "jkl",
+		},
+		{
+			name:   "no token",
+			header: func(h http.Header) { h.Add("Set-Cookie", "ttwid=1; Path=/") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.header(w.Header())
+			}))
+			defer srv.Close()
+			resp, err := resty.New().R().Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, expires := msTokenFromResponse(resp)
+			if token != tt.want {
+				t.Errorf("token = %q, want %q", token, tt.want)
+			}
+			if got := !expires.IsZero(); got != tt.wantExpiry {
+				t.Errorf("expires = %v, want set: %v", expires, tt.wantExpiry)
+			}
+			if tt.wantExpiry && time.Until(expires) < 50*time.Minute {
+				t.Errorf("expires = %v, want about one hour from now", expires)
+			}
+		})
+	}
+}
+
+// stubMsTokenCache 返回使用 fetch 获取 msToken 的缓存，并统计调用次数
+func stubMsTokenCache(fetch func(ctx context.Context, current string) (string, time.Time, error)) (*msTokenCache, *atomic.Int32) {
+	var calls atomic.Int32
+	m := &msTokenCache{}
+	m.fetchToken = func(ctx context.Context, current string) (string, time.Time, error) {
+		calls.Add(1)
+		return fetch(ctx, current)
+	}
+	return m, &calls
+}
+
+func TestMsTokenCacheExpiry(t *testing.T) {
+	m, calls := stubMsTokenCache(func(ctx context.Context, current string) (string, time.Time, error) {
+		return "fresh", time.Time{}, nil
+	})
+
+	// 未过期的缓存直接使用
+	m.token, m.expires = "cached", time.Now().Add(10*time.Minute)
+	if got, err := m.get(context.Background()); err != nil || got != "cached" {
+		t.Fatalf("get() = %q, %v, want cached", got, err)
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("fetch called %d times for a valid token", calls.Load())
+	}
+
+	// 即将过期时提前刷新，未返回有效期的按 msTokenTTL 缓存
+	m.expires = time.Now().Add(msTokenRefreshAhead / 2)
+	if got, err := m.get(context.Background()); err != nil || got != "fresh" {
+		t.Fatalf("get() = %q, %v, want fresh", got, err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("fetch called %d times, want 1", calls.Load())
+	}
+	if d := time.Until(m.expires); d < msTokenTTL-time.Minute {
+		t.Errorf("expires in %v, want about %v", d, msTokenTTL)
+	}
+	if got, _ := m.get(context.Background()); got != "fresh" || calls.Load() != 1 {
+		t.Errorf("second get() = %q after %d fetches, want cached fresh token", got, calls.Load())
+	}
+}
+
+func TestMsTokenCacheFallback(t *testing.T) {
+	failing := func(ctx context.Context, current string) (string, time.Time, error) {
+		return "", time.Time{}, errors.New("report failed")
+	}
+
+	m, _ := stubMsTokenCache(failing)
+	if _, err := m.get(context.Background()); err == nil {
+		t.Error("get() without random fallback expected error")
+	}
+
+	m, calls := stubMsTokenCache(failing)
+	m.randomFallback = true
+	token, err := m.get(context.Background())
+	if err != nil || len(token) != msTokenLength {
+		t.Fatalf("get() = %q, %v, want random token", token, err)
+	}
+	if d := time.Until(m.expires); d > randomMsTokenTTL || d < randomMsTokenTTL-time.Minute {
+		t.Errorf("random token expires in %v, want %v", d, randomMsTokenTTL)
+	}
+	if again, _ := m.get(context.Background()); again != token || calls.Load() != 1 {
+		t.Errorf("random token should be cached, got %q after %d fetches", again, calls.Load())
+	}
+}
+
+func TestMsTokenCacheConcurrentFetch(t *testing.T) {
+	release := make(chan struct{})
+	m, calls := stubMsTokenCache(func(ctx context.Context, current string) (string, time.Time, error) {
+		<-release
+		return "shared", time.Now().Add(time.Hour), nil
+	})
+
+	// 一个等待者的 ctx 取消后应立即返回，而不是等待上报结束
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := m.get(ctx)
+		cancelled <- err
+	}()
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = m.get(context.Background())
+		}(i)
+	}
+
+	cancel()
+	select {
+	case err := <-cancelled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("cancelled get() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancelled get() blocked on the in-flight fetch")
+	}
+
+	close(release)
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Errorf("fetch called %d times, want 1", calls.Load())
+	}
+	for i, got := range results {
+		if got != "shared" {
+			t.Errorf("results[%d] = %q, want shared", i, got)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package douyin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Visibility 作品可见范围
+type Visibility int
+
+const (
+	// VisibilityPublic 公开
+	VisibilityPublic Visibility = 0
+	// VisibilityPrivate 仅自己可见
+	VisibilityPrivate Visibility = 1
+	// VisibilityFriends 好友可见
+	VisibilityFriends Visibility = 2
+)
+
+// text_extra 中片段的类型
+const (
+	textExtraMention = 0
+	textExtraHashtag = 1
+	textExtraTitle   = 7
+)
+
+// 定时发布允许的时间范围: 当前时间 2 小时后至 14 天内
+const (
+	minScheduleDelay = 2 * time.Hour
+	maxScheduleDelay = 14 * 24 * time.Hour
+)
+
+// PublishOptions 发布作品的通用选项
+type PublishOptions struct {
+	// Hashtags 话题名称 (不带 #)，会被解析为话题 ID 并追加到正文末尾
+	Hashtags []string
+	// Mentions 需要 @ 的用户，可以是抖音号或昵称，会被解析为用户 ID
+	Mentions []string
+	// Visibility 可见范围
+	Visibility Visibility
+	// DisableDownload 为 true 时不允许他人下载作品
+	DisableDownload bool
+	// ScheduleTime 定时发布时间，零值表示立即发布
+	ScheduleTime time.Time
+	// POI 位置信息，为空时不挂载位置
+	POI *POI
+	// AIGenerated 声明内容由 AI 生成
+	AIGenerated bool
+}
+
+// POI 位置信息
+type POI struct {
+	ID      string `json:"poi_id"`
+	Name    string `json:"poi_name"`
+	Address string `json:"address"`
+}
+
+// TextExtra 正文中话题、@用户等片段的描述，偏移量按 UTF-16 编码单元计算
+type TextExtra struct {
+	Start        int    `json:"start"`
+	End          int    `json:"end"`
+	Type         int    `json:"type"`
+	HashtagID    int64  `json:"hashtag_id"`
+	HashtagName  string `json:"hashtag_name"`
+	UserID       string `json:"user_id,omitempty"`
+	SecUID       string `json:"sec_uid,omitempty"`
+	CaptionStart int    `json:"caption_start"`
+	CaptionEnd   int    `json:"caption_end"`
+}
+
+// Hashtag 话题
+type Hashtag struct {
+	ID        int64  `json:"cid,string"`
+	Name      string `json:"cha_name"`
+	ViewCount int64  `json:"view_count"`
+}
+
+// MentionUser 可以 @ 的用户
+type MentionUser struct {
+	UID      string `json:"uid"`
+	SecUID   string `json:"sec_uid"`
+	Nickname string `json:"nickname"`
+	UniqueID string `json:"unique_id"`
+}
+
+// SearchHashtags 按关键词搜索话题
+func (c *DyClient) SearchHashtags(ctx context.Context, keyword string) ([]Hashtag, error) {
+	var result struct {
+		StatusCode int       `json:"status_code"`
+		SugList    []Hashtag `json:"sug_list"`
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", "https://creator.douyin.com/creator-micro/content/post").
+		SetQueryParams(map[string]string{
+			"keyword": keyword,
+			"source":  "challenge_create",
+			"aid":     "2906",
+		}).
+		Get(fmt.Sprintf("%s/aweme/v1/search/challengesug/", douyinCreatorURL))
+	if err != nil {
+		return nil, err
+	}
+	if result.StatusCode != 0 {
+		return nil, fmt.Errorf("search hashtag failed, status code: %d, body: %s", result.StatusCode, resp.String())
+	}
+	return result.SugList, nil
+}
+
+// SearchMentionUsers 按关键词搜索可以 @ 的用户
+func (c *DyClient) SearchMentionUsers(ctx context.Context, keyword string) ([]MentionUser, error) {
+	var result struct {
+		StatusCode int `json:"status_code"`
+		UserList   []struct {
+			UserInfo MentionUser `json:"user_info"`
+		} `json:"user_list"`
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", "https://creator.douyin.com/creator-micro/content/post").
+		SetQueryParams(map[string]string{
+			"keyword":       keyword,
+			"count":         "10",
+			"type":          "1",
+			"search_source": "publish_at",
+			"aid":           "2906",
+		}).
+		Get(fmt.Sprintf("%s/aweme/v1/discover/search/", douyinCreatorURL))
+	if err != nil {
+		return nil, err
+	}
+	if result.StatusCode != 0 {
+		return nil, fmt.Errorf("search mention user failed, status code: %d, body: %s", result.StatusCode, resp.String())
+	}
+	users := make([]MentionUser, 0, len(result.UserList))
+	for _, u := range result.UserList {
+		users = append(users, u.UserInfo)
+	}
+	return users, nil
+}
+
+// SearchPOI 按关键词搜索可挂载的位置
+func (c *DyClient) SearchPOI(ctx context.Context, keyword string) ([]POI, error) {
+	var result struct {
+		StatusCode int   `json:"status_code"`
+		PoiList    []POI `json:"poi_list"`
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", "https://creator.douyin.com/creator-micro/content/post").
+		SetQueryParams(map[string]string{
+			"keyword": keyword,
+			"count":   "20",
+			"aid":     "2906",
+		}).
+		Get(fmt.Sprintf("%s/aweme/v1/life/poi/search/", douyinCreatorURL))
+	if err != nil {
+		return nil, err
+	}
+	if result.StatusCode != 0 {
+		return nil, fmt.Errorf("search poi failed, status code: %d, body: %s", result.StatusCode, resp.String())
+	}
+	return result.PoiList, nil
+}
+
+// resolveHashtag 将话题名称解析为话题，没有完全匹配的已有话题时返回 ID 为 0 的新话题
+func (c *DyClient) resolveHashtag(ctx context.Context, name string) (Hashtag, error) {
+	tags, err := c.SearchHashtags(ctx, name)
+	if err != nil {
+		return Hashtag{}, err
+	}
+	for _, t := range tags {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Hashtag{Name: name}, nil
+}
+
+// composeText 拼接标题、正文、话题与 @ 用户，返回作品文本及其 text_extra
+// text_extra 的偏移量按 UTF-16 编码单元计算，与网页端 JavaScript 字符串下标一致
+func composeText(title, description string, tags []Hashtag, users []MentionUser) (string, []TextExtra) {
+	var (
+		text       strings.Builder
+		offset     int
+		textExtras = []TextExtra{}
+	)
+	appendText := func(s string) (start, end int) {
+		start = offset
+		text.WriteString(s)
+		offset += len(utf16.Encode([]rune(s)))
+		return start, offset
+	}
+
+	if title != "" {
+		start, end := appendText(title)
+		textExtras = append(textExtras, TextExtra{Start: start, End: end, Type: textExtraTitle})
+	}
+	if description != "" {
+		if title != "" {
+			appendText("\n")
+		}
+		appendText(description)
+	}
+	for _, tag := range tags {
+		if offset > 0 {
+			appendText(" ")
+		}
+		start, end := appendText("#" + tag.Name)
+		textExtras = append(textExtras, TextExtra{
+			Start:        start,
+			End:          end,
+			Type:         textExtraHashtag,
+			HashtagID:    tag.ID,
+			HashtagName:  tag.Name,
+			CaptionStart: start,
+			CaptionEnd:   end,
+		})
+	}
+	for _, user := range users {
+		if offset > 0 {
+			appendText(" ")
+		}
+		start, end := appendText("@" + user.Nickname)
+		textExtras = append(textExtras, TextExtra{
+			Start:        start,
+			End:          end,
+			Type:         textExtraMention,
+			UserID:       user.UID,
+			SecUID:       user.SecUID,
+			CaptionStart: start,
+			CaptionEnd:   end,
+		})
+	}
+	return text.String(), textExtras
+}
+
+// resolveMention 将抖音号或昵称解析为用户，要求完全匹配
+func (c *DyClient) resolveMention(ctx context.Context, name string) (MentionUser, error) {
+	users, err := c.SearchMentionUsers(ctx, name)
+	if err != nil {
+		return MentionUser{}, err
+	}
+	for _, u := range users {
+		if u.UniqueID == name || u.Nickname == name {
+			return u, nil
+		}
+	}
+	return MentionUser{}, fmt.Errorf("mention user %q not found", name)
+}
+
+// resolveTextEntities 将话题名称与抖音号/昵称解析为 composeText 需要的话题与用户，空白项会被忽略
+func (c *DyClient) resolveTextEntities(ctx context.Context, hashtags, mentions []string) ([]Hashtag, []MentionUser, error) {
+	var tags []Hashtag
+	for _, name := range hashtags {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+		if name == "" {
+			continue
+		}
+		tag, err := c.resolveHashtag(ctx, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolve hashtag %q failed: %w", name, err)
+		}
+		tags = append(tags, tag)
+	}
+
+	var users []MentionUser
+	for _, name := range mentions {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+		if name == "" {
+			continue
+		}
+		user, err := c.resolveMention(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		users = append(users, user)
+	}
+	return tags, users, nil
+}
+
+// buildItem 根据标题、正文和发布选项生成作品的正文、话题、可见性等公共字段，
+// 媒体相关字段 (MediaType、Images、VideoID、Cover) 由调用方填充
+func (c *DyClient) buildItem(ctx context.Context, title, description string, opts *PublishOptions) (Item, error) {
+	if opts == nil {
+		opts = &PublishOptions{}
+	}
+
+	tags, users, err := c.resolveTextEntities(ctx, opts.Hashtags, opts.Mentions)
+	if err != nil {
+		return Item{}, err
+	}
+
+	text, textExtras := composeText(title, description, tags, users)
+	challenges := []string{}
+	for _, tag := range tags {
+		if tag.ID != 0 {
+			challenges = append(challenges, fmt.Sprintf("%d", tag.ID))
+		}
+	}
+	mentions := []string{}
+	for _, user := range users {
+		mentions = append(mentions, user.UID)
+	}
+
+	timing := int64(-1)
+	if !opts.ScheduleTime.IsZero() {
+		delay := time.Until(opts.ScheduleTime)
+		if delay < minScheduleDelay || delay > maxScheduleDelay {
+			return Item{}, fmt.Errorf("schedule time must be between %s and %s from now", minScheduleDelay, maxScheduleDelay)
+		}
+		timing = opts.ScheduleTime.Unix()
+	}
+
+	download := 1
+	if opts.DisableDownload {
+		download = 0
+	}
+
+	declare := map[string]interface{}{}
+	if opts.AIGenerated {
+		declare["choose_value"] = "aigc"
+	}
+
+	textExtraJSON, err := json.Marshal(textExtras)
+	if err != nil {
+		return Item{}, err
+	}
+	challengesJSON, _ := json.Marshal(challenges)
+	mentionsJSON, _ := json.Marshal(mentions)
+	declareJSON, _ := json.Marshal(declare)
+
+	item := Item{
+		Common: Common{
+			Text:           text,
+			TextExtra:      string(textExtraJSON),
+			Activity:       "[]",
+			Challenges:     string(challengesJSON),
+			HashtagSource:  "",
+			Mentions:       string(mentionsJSON),
+			VisibilityType: int(opts.Visibility),
+			Download:       download,
+			Timing:         timing,
+			// Generate a creation_id, it seems to be a timestamp-based unique id.
+			CreationID: fmt.Sprintf("gemini%d", time.Now().UnixNano()/1e6),
+		},
+		Declare: Declare{
+			UserDeclareInfo: string(declareJSON),
+		},
+	}
+	if opts.POI != nil {
+		item.Anchor = Anchor{PoiID: opts.POI.ID, PoiName: opts.POI.Name}
+	}
+	if len(challenges) > 0 {
+		item.Common.HashtagSource = "recommend"
+	}
+	return item, nil
+}
@@ -0,0 +1,74 @@
+package douyin
+
+import (
+	"testing"
+	"unicode/utf16"
+)
+
+// utf16Slice 按 UTF-16 下标截取，与网页端 JavaScript 的 substring 一致
+func utf16Slice(s string, start, end int) string {
+	u := utf16.Encode([]rune(s))
+	return string(utf16.Decode(u[start:end]))
+}
+
+func TestComposeText(t *testing.T) {
+	tags := []Hashtag{{ID: 1001, Name: "美食😋"}, {Name: "探店"}}
+	users := []MentionUser{{UID: "42", SecUID: "MS4w", Nickname: "小明🐱"}}
+
+	text, extras := composeText("周末 🍜 好去处", "第二行：😀😀", tags, users)
+	wantText := "周末 🍜 好去处\n第二行：😀😀 #美食😋 #探店 @小明🐱"
+	if text != wantText {
+		t.Fatalf("text = %q, want %q", text, wantText)
+	}
+
+	want := []struct {
+		typ     int
+		segment string
+	}{
+		{textExtraTitle, "周末 🍜 好去处"},
+		{textExtraHashtag, "#美食😋"},
+		{textExtraHashtag, "#探店"},
+		{textExtraMention, "@小明🐱"},
+	}
+	if len(extras) != len(want) {
+		t.Fatalf("got %d text extras, want %d: %+v", len(extras), len(want), extras)
+	}
+	for i, w := range want {
+		e := extras[i]
+		if e.Type != w.typ {
+			t.Errorf("extras[%d].Type = %d, want %d", i, e.Type, w.typ)
+		}
+		if got := utf16Slice(text, e.Start, e.End); got != w.segment {
+			t.Errorf("extras[%d] covers %q, want %q", i, got, w.segment)
+		}
+		if e.Type != textExtraTitle && (e.CaptionStart != e.Start || e.CaptionEnd != e.End) {
+			t.Errorf("extras[%d] caption range = [%d,%d), want [%d,%d)", i, e.CaptionStart, e.CaptionEnd, e.Start, e.End)
+		}
+	}
+
+	// 🍜 占两个 UTF-16 单元，标题共 9 个单元，而 UTF-8 为 20 字节
+	if extras[0].End != 9 {
+		t.Errorf("title end = %d, want 9", extras[0].End)
+	}
+	if extras[1].HashtagID != 1001 || extras[1].HashtagName != "美食😋" {
+		t.Errorf("hashtag extra = %+v", extras[1])
+	}
+	if extras[3].UserID != "42" || extras[3].SecUID != "MS4w" {
+		t.Errorf("mention extra = %+v", extras[3])
+	}
+}
+
+func TestComposeTextWithoutTitle(t *testing.T) {
+	text, extras := composeText("", "", []Hashtag{{Name: "话题"}}, nil)
+	if text != "#话题" {
+		t.Errorf("text = %q, want %q", text, "#话题")
+	}
+	if len(extras) != 1 || extras[0].Start != 0 || extras[0].End != 3 {
+		t.Errorf("extras = %+v", extras)
+	}
+
+	text, extras = composeText("", "", nil, nil)
+	if text != "" || extras == nil || len(extras) != 0 {
+		t.Errorf("empty composeText() = %q, %#v, want empty text and non-nil extras", text, extras)
+	}
+}
@@ -0,0 +1,179 @@
+package douyin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// 关注/粉丝列表的状态码
+const (
+	// statusPrivateAccount 对方设置了隐私，不公开关注/粉丝列表
+	statusPrivateAccount = 2079
+)
+
+// ErrPrivateAccount 对方未公开关注/粉丝列表
+var ErrPrivateAccount = errors.New("user's relation list is private")
+
+// ListDepthError 平台对列表可翻页深度有上限，到达上限后无法继续获取剩余数据
+type ListDepthError struct {
+	Fetched int
+	Total   int
+}
+
+func (e *ListDepthError) Error() string {
+	return fmt.Sprintf("list depth limit reached: fetched %d of %d users", e.Fetched, e.Total)
+}
+
+// UserSummary 列表中的用户摘要信息
+type UserSummary struct {
+	UID            string `json:"uid"`
+	SecUID         string `json:"sec_uid"`
+	UniqueID       string `json:"unique_id"`
+	ShortID        string `json:"short_id"`
+	Nickname       string `json:"nickname"`
+	Signature      string `json:"signature"`
+	FollowerCount  int64  `json:"follower_count"`
+	FollowingCount int64  `json:"following_count"`
+	AwemeCount     int64  `json:"aweme_count"`
+	FollowStatus   int    `json:"follow_status"`
+	Secret         int    `json:"secret"`
+	AvatarThumb    struct {
+		URLList []string `json:"url_list"`
+	} `json:"avatar_thumb"`
+}
+
+// IsPrivate 该用户是否为私密账号
+func (u *UserSummary) IsPrivate() bool {
+	return u.Secret == 1
+}
+
+// UserIterator 以 max_time 游标遍历关注/粉丝列表
+//
+//	it, err := client.ListFollowers(ctx, secUserID)
+//	if err != nil {
+//		...
+//	}
+//	for it.HasMore() {
+//		users, err := it.Next(ctx)
+//		...
+//	}
+type UserIterator struct {
+	c         *DyClient
+	path      string
+	listKey   string
+	secUserID string
+	count     int
+
+	maxTime int64
+	hasMore bool
+	fetched int
+
+	// first 构造迭代器时已获取的第一页，由第一次 Next 返回
+	first      []UserSummary
+	firstErr   error
+	firstReady bool
+}
+
+// ListFollowers 获取用户粉丝列表的第一页并返回迭代器
+// 对方未公开粉丝列表时返回 ErrPrivateAccount
+func (c *DyClient) ListFollowers(ctx context.Context, secUserID string) (*UserIterator, error) {
+	return c.listRelation(ctx, "/aweme/v1/web/user/follower/list/", "followers", secUserID)
+}
+
+// ListFollowing 获取用户关注列表的第一页并返回迭代器
+// 对方未公开关注列表时返回 ErrPrivateAccount
+func (c *DyClient) ListFollowing(ctx context.Context, secUserID string) (*UserIterator, error) {
+	return c.listRelation(ctx, "/aweme/v1/web/user/following/list/", "followings", secUserID)
+}
+
+func (c *DyClient) listRelation(ctx context.Context, path, listKey, secUserID string) (*UserIterator, error) {
+	it := &UserIterator{
+		c:         c,
+		path:      path,
+		listKey:   listKey,
+		secUserID: secUserID,
+		count:     20,
+		hasMore:   true,
+	}
+	users, err := it.fetch(ctx)
+	var depthErr *ListDepthError
+	if err != nil && !errors.As(err, &depthErr) {
+		return nil, err
+	}
+	it.first, it.firstErr, it.firstReady = users, err, true
+	return it, nil
+}
+
+// HasMore 是否还有下一页
+func (it *UserIterator) HasMore() bool {
+	return it.firstReady || it.hasMore
+}
+
+// Next 获取下一页用户
+// 当平台因列表深度上限停止返回数据时，会返回本页数据以及 *ListDepthError
+func (it *UserIterator) Next(ctx context.Context) ([]UserSummary, error) {
+	if it.firstReady {
+		users, err := it.first, it.firstErr
+		it.first, it.firstErr, it.firstReady = nil, nil, false
+		return users, err
+	}
+	if !it.hasMore {
+		return nil, nil
+	}
+	return it.fetch(ctx)
+}
+
+// fetch 请求 maxTime 之前的一页用户并更新游标
+func (it *UserIterator) fetch(ctx context.Context) ([]UserSummary, error) {
+	var result struct {
+		BaseResponse
+		Followers  []UserSummary `json:"followers"`
+		Followings []UserSummary `json:"followings"`
+		HasMore    bool          `json:"has_more"`
+		MinTime    int64         `json:"min_time"`
+		MaxTime    int64         `json:"max_time"`
+		Total      int           `json:"total"`
+	}
+	_, err := it.c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", douyinWebURL+"/user/"+it.secUserID).
+		SetQueryParams(it.c.webQueryParams(map[string]string{
+			"sec_user_id":         it.secUserID,
+			"offset":              "0",
+			"min_time":            "0",
+			"max_time":            strconv.FormatInt(it.maxTime, 10),
+			"count":               strconv.Itoa(it.count),
+			"source_type":         "1",
+			"gps_access":          "0",
+			"address_book_access": "0",
+			"is_top":              "1",
+		})).
+		Get(douyinWebURL + it.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.StatusCode == statusPrivateAccount {
+		it.hasMore = false
+		return nil, ErrPrivateAccount
+	}
+	if err := result.check("list " + it.listKey); err != nil {
+		return nil, err
+	}
+
+	users := result.Followers
+	if it.listKey == "followings" {
+		users = result.Followings
+	}
+	it.fetched += len(users)
+	it.maxTime = result.MinTime
+	it.hasMore = result.HasMore && len(users) > 0
+
+	if !it.hasMore && result.Total > it.fetched {
+		return users, &ListDepthError{Fetched: it.fetched, Total: result.Total}
+	}
+	return users, nil
+}
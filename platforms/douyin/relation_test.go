@@ -0,0 +1,152 @@
+package douyin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// relationPages 按 max_time 返回关注/粉丝列表的分页响应
+func relationPages(t *testing.T, requests *[]string, pages map[string]string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		maxTime := r.URL.Query().Get("max_time")
+		*requests = append(*requests, maxTime)
+		body, ok := pages[maxTime]
+		if !ok {
+			t.Errorf("unexpected max_time %q", maxTime)
+		}
+		return body
+	}
+}
+
+func TestListFollowersPaging(t *testing.T) {
+	var requests []string
+	c := stubClient(relationPages(t, &requests, map[string]string{
+		"0":   `{"status_code":0,"has_more":true,"min_time":100,"total":3,"followers":[{"uid":"1"},{"uid":"2"}]}`,
+		"100": `{"status_code":0,"has_more":false,"min_time":50,"total":3,"followers":[{"uid":"3"}]}`,
+	}))
+	ctx := context.Background()
+
+	it, err := c.ListFollowers(ctx, "sec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("ListFollowers() sent %d requests, want the first page only", len(requests))
+	}
+	var uids []string
+	for it.HasMore() {
+		users, err := it.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range users {
+			uids = append(uids, u.UID)
+		}
+	}
+	if strings.Join(uids, ",") != "1,2,3" || strings.Join(requests, ",") != "0,100" {
+		t.Errorf("users = %v, requested max_time = %v", uids, requests)
+	}
+	if users, err := it.Next(ctx); users != nil || err != nil || len(requests) != 2 {
+		t.Errorf("Next() after end = %v, %v, %d requests", users, err, len(requests))
+	}
+}
+
+func TestListFollowingUsesFollowingsKey(t *testing.T) {
+	var requests []string
+	c := stubClient(relationPages(t, &requests, map[string]string{
+		"0": `{"status_code":0,"has_more":false,"total":1,"followers":[{"uid":"wrong"}],"followings":[{"uid":"1"}]}`,
+	}))
+	it, err := c.ListFollowing(context.Background(), "sec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users, err := it.Next(context.Background()); err != nil || len(users) != 1 || users[0].UID != "1" {
+		t.Errorf("Next() = %+v, %v", users, err)
+	}
+}
+
+func TestListFollowersErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr func(error) bool
+	}{
+		{"private account", `{"status_code":2079,"status_msg":"私密账号"}`, func(err error) bool { return errors.Is(err, ErrPrivateAccount) }},
+		{"other failure", `{"status_code":8,"status_msg":"未登录"}`, func(err error) bool {
+			return err != nil && err.Error() == "list followers failed, status code: 8, msg: 未登录"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := stubClient(func(r *http.Request) string { return tt.body })
+			it, err := c.ListFollowers(context.Background(), "sec")
+			if it != nil || !tt.wantErr(err) {
+				t.Errorf("ListFollowers() = %v, %v", it, err)
+			}
+		})
+	}
+}
+
+func TestListFollowersDepthLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     map[string]string
+		wantPages int
+		fetched   int
+	}{
+		{
+			name: "first page",
+			pages: map[string]string{
+				"0": `{"status_code":0,"has_more":false,"min_time":100,"total":5000,"followers":[{"uid":"1"},{"uid":"2"}]}`,
+			},
+			wantPages: 1,
+			fetched:   2,
+		},
+		{
+			name: "later page",
+			pages: map[string]string{
+				"0":   `{"status_code":0,"has_more":true,"min_time":100,"total":5000,"followers":[{"uid":"1"}]}`,
+				"100": `{"status_code":0,"has_more":true,"min_time":90,"total":5000,"followers":[]}`,
+			},
+			wantPages: 2,
+			fetched:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			c := stubClient(relationPages(t, &requests, tt.pages))
+			ctx := context.Background()
+
+			// 第一页即到达深度上限时，构造函数仍返回迭代器，错误随第一页由 Next 返回
+			it, err := c.ListFollowers(ctx, "sec")
+			if err != nil {
+				t.Fatalf("ListFollowers() error = %v", err)
+			}
+			var (
+				pages    int
+				depthErr *ListDepthError
+				last     []UserSummary
+			)
+			for it.HasMore() {
+				last, err = it.Next(ctx)
+				pages++
+				if err != nil {
+					break
+				}
+			}
+			if !errors.As(err, &depthErr) || depthErr.Fetched != tt.fetched || depthErr.Total != 5000 {
+				t.Fatalf("Next() error = %v, want ListDepthError{%d, 5000}", err, tt.fetched)
+			}
+			if pages != tt.wantPages || it.HasMore() {
+				t.Errorf("pages = %d, HasMore = %v, want %d pages and no more", pages, it.HasMore(), tt.wantPages)
+			}
+			if tt.wantPages == 1 && len(last) != 2 {
+				t.Errorf("first page users = %+v, want them returned with the error", last)
+			}
+		})
+	}
+}
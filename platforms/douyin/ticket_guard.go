@@ -0,0 +1,53 @@
+package douyin
+
+import (
+	"context"
+	"crawler-sdk/internal/crypto/dy/bd"
+	"crawler-sdk/pkg/http"
+	"errors"
+)
+
+// ticketGuardRegisterURL 携带 CSR 请求后，passport 接口在 bd-ticket-guard-server-data 响应头中下发 ticket 与 ts_sign
+const ticketGuardRegisterURL = douyinWebURL + "/passport/web/account/info/"
+
+// ErrNoTicketGuardServerData passport 接口未下发 ticket-guard 凭证，通常是 cookie 未登录或已失效
+var ErrNoTicketGuardServerData = errors.New("no bd-ticket-guard-server-data in response")
+
+// RegisterTicketGuard 使用登录态 cookie 将凭证中的 CSR 提交给服务端，换取 ticket、ts_sign 与 client_cert
+func RegisterTicketGuard(ctx context.Context, cookie string, creds *bd.Credentials) error {
+	resp, err := http.NewClient(cookie).R().
+		SetContext(ctx).
+		SetHeaders(map[string]string{
+			"Referer":                           douyinWebURL + "/",
+			"bd-ticket-guard-client-csr":        creds.ClientCSR(),
+			"bd-ticket-guard-version":           "2",
+			"bd-ticket-guard-web-version":       "2",
+			"bd-ticket-guard-iteration-version": "1",
+		}).
+		Get(ticketGuardRegisterURL)
+	if err != nil {
+		return err
+	}
+	data := resp.Header().Get("bd-ticket-guard-server-data")
+	if data == "" {
+		return ErrNoTicketGuardServerData
+	}
+	return creds.ApplyServerData(data)
+}
+
+// NewTicketGuardSigner 为登录态 cookie 生成新的 ticket-guard 凭证并完成注册
+// 返回的凭证应持久化保存，之后通过 bd.NewBDSignerFromCredentials 恢复，无需重复注册
+func NewTicketGuardSigner(ctx context.Context, cookie string) (*bd.BDSigner, *bd.Credentials, error) {
+	creds, err := bd.NewCredentials()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := RegisterTicketGuard(ctx, cookie, creds); err != nil {
+		return nil, nil, err
+	}
+	signer, err := bd.NewBDSignerFromCredentials(creds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return signer, creds, nil
+}
@@ -0,0 +1,179 @@
+package douyin
+
+import (
+	"context"
+	"strconv"
+)
+
+// HotSearchItem 热榜中的一个词条
+type HotSearchItem struct {
+	Word       string `json:"word"`
+	SentenceID string `json:"sentence_id"`
+	HotValue   int64  `json:"hot_value"`
+	Position   int    `json:"position"`
+	Label      int    `json:"label"`
+	EventTime  int64  `json:"event_time"`
+	VideoCount int    `json:"video_count"`
+	GroupID    string `json:"group_id"`
+}
+
+// HotSearchBoard 抖音热榜
+type HotSearchBoard struct {
+	ActiveTime string          `json:"active_time"`
+	Words      []HotSearchItem `json:"word_list"`
+	Trending   []HotSearchItem `json:"trending_list"`
+}
+
+// Challenge 话题 (挑战)
+type Challenge struct {
+	CID       string `json:"cid"`
+	Name      string `json:"cha_name"`
+	Desc      string `json:"desc"`
+	ViewCount int64  `json:"view_count"`
+	UserCount int64  `json:"user_count"`
+	Type      int    `json:"type"`
+	ShareInfo struct {
+		ShareURL string `json:"share_url"`
+	} `json:"share_info"`
+}
+
+// awemeListResponse 作品列表接口的通用响应
+type awemeListResponse struct {
+	BaseResponse
+	AwemeList []Aweme `json:"aweme_list"`
+	Cursor    int64   `json:"cursor"`
+	HasMore   int     `json:"has_more"`
+}
+
+func (r *awemeListResponse) page() *AwemePage {
+	return &AwemePage{
+		Awemes:  r.AwemeList,
+		Cursor:  r.Cursor,
+		HasMore: r.HasMore == 1,
+	}
+}
+
+// GetHotSearchBoard 获取抖音热榜
+func (c *DyClient) GetHotSearchBoard(ctx context.Context) (*HotSearchBoard, error) {
+	var result struct {
+		BaseResponse
+		Data HotSearchBoard `json:"data"`
+	}
+	_, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", douyinWebURL+"/hot").
+		SetQueryParams(c.webQueryParams(map[string]string{
+			"detail_list":    "1",
+			"source":         "6",
+			"board_type":     "0",
+			"board_sub_type": "",
+		})).
+		Get(douyinWebURL + "/aweme/v1/web/hot/search/list/")
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get hot search board"); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// GetChallengeDetail 获取话题详情 (播放量、参与人数、描述)
+func (c *DyClient) GetChallengeDetail(ctx context.Context, challengeID string) (*Challenge, error) {
+	var result struct {
+		BaseResponse
+		ChInfo Challenge `json:"ch_info"`
+	}
+	_, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", douyinWebURL+"/hashtag/"+challengeID).
+		SetQueryParams(c.webQueryParams(map[string]string{
+			"ch_id": challengeID,
+		})).
+		Get(douyinWebURL + "/aweme/v1/web/challenge/detail/")
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get challenge detail"); err != nil {
+		return nil, err
+	}
+	return &result.ChInfo, nil
+}
+
+// ListChallengeAwemes 分页获取话题下的作品
+// cursor: 上一页返回的 Cursor，首页传 0
+func (c *DyClient) ListChallengeAwemes(ctx context.Context, challengeID string, cursor int64, count int) (*AwemePage, error) {
+	if count <= 0 {
+		count = 20
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&awemeListResponse{}).
+		SetHeader("Referer", douyinWebURL+"/hashtag/"+challengeID).
+		SetQueryParams(c.webQueryParams(map[string]string{
+			"ch_id":     challengeID,
+			"cursor":    strconv.FormatInt(cursor, 10),
+			"count":     strconv.Itoa(count),
+			"sort_type": "0",
+		})).
+		Get(douyinWebURL + "/aweme/v1/web/challenge/aweme/")
+	if err != nil {
+		return nil, err
+	}
+	result := resp.Result().(*awemeListResponse)
+	if err := result.check("list challenge awemes"); err != nil {
+		return nil, err
+	}
+	return result.page(), nil
+}
+
+// GetMusicDetail 获取音乐详情
+func (c *DyClient) GetMusicDetail(ctx context.Context, musicID string) (*Music, error) {
+	var result struct {
+		BaseResponse
+		MusicInfo Music `json:"music_info"`
+	}
+	_, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetHeader("Referer", douyinWebURL+"/music/"+musicID).
+		SetQueryParams(c.webQueryParams(map[string]string{
+			"music_id": musicID,
+		})).
+		Get(douyinWebURL + "/aweme/v1/web/music/detail/")
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get music detail"); err != nil {
+		return nil, err
+	}
+	return &result.MusicInfo, nil
+}
+
+// ListMusicAwemes 分页获取使用某个音乐的作品
+// cursor: 上一页返回的 Cursor，首页传 0
+func (c *DyClient) ListMusicAwemes(ctx context.Context, musicID string, cursor int64, count int) (*AwemePage, error) {
+	if count <= 0 {
+		count = 20
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(&awemeListResponse{}).
+		SetHeader("Referer", douyinWebURL+"/music/"+musicID).
+		SetQueryParams(c.webQueryParams(map[string]string{
+			"music_id": musicID,
+			"cursor":   strconv.FormatInt(cursor, 10),
+			"count":    strconv.Itoa(count),
+		})).
+		Get(douyinWebURL + "/aweme/v1/web/music/aweme/")
+	if err != nil {
+		return nil, err
+	}
+	result := resp.Result().(*awemeListResponse)
+	if err := result.check("list music awemes"); err != nil {
+		return nil, err
+	}
+	return result.page(), nil
+}
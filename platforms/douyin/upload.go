@@ -97,31 +97,17 @@ type PluginResult struct {
 
 // getUploadAuth 获取上传凭证
 func (c *DyClient) getUploadAuth(ctx context.Context) (*AuthDetails, error) {
-	// TODO: msToken 和 a_bogus 参数需要动态生成
-	// cookie_enabled	true
-	// screen_width	2048
-	// screen_height	1152
-	// browser_language	zh-CN
-	// browser_platform	Win32
-	// browser_name	Mozilla
-	// browser_version	5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36 Edg/139.0.0.0
-	// browser_online	true
-	// timezone_name	Asia/Shanghai
-	// aid	1128
-	// support_h265	1
-	// msToken	E-qrN5_3s_GdJMz0_nDQm3nwc-Y4lyv0AAZPLCxZkofS0bz-I7dnsoY4_kVfOvQ5mvIHR6yYv6fJqx9OmOkaqd1Hju98GuCPs00pZecchjGT2n5fCx3cJxTD2sozSKRsZNCOLKfn6--0rMR9yexL9ml_hVToYy111jzJzOFeBNwc
-	// a_bogus	Qv4VhFtEQx85OpFGuKEtC31UEWxlNP8yqlTQbzLn9PxKOZUGDZHakcGeGxztf7uxnYBVkKVHsfsAbxxbTUkzZA9pzmkfSNt6jzVInX8o01qDbzvsErjDSL6FoXsc8bGulQ5yiAXfMUt72xO-NrdD/p-Hy/bF5QmkQrQRk/zGOoG11zyAE1c-PptkihiKUenJ
 	resp, err := c.client.R().
 		SetHeaders(map[string]string{
 			"Referer": "https://creator.douyin.com/creator-micro/content/post",
 		}).
 		SetResult(&UploadAuthResponse{}).
 		SetQueryParams(map[string]string{
-			"aid":             "1128",
-			"support_h265":    "1",
-			"cookie_enabled":  "true",
-			"screen_width":    "2048",
-			"screen_height":   "1152",
+			"aid":              "1128",
+			"support_h265":     "1",
+			"cookie_enabled":   "true",
+			"screen_width":     "2048",
+			"screen_height":    "1152",
 			"browser_language": "zh-CN",
 			"browser_platform": "Win32",
 			"browser_name":     "Mozilla",
@@ -279,9 +265,10 @@ type Common struct {
 	Mentions       string  `json:"mentions"`
 	VisibilityType int     `json:"visibility_type"`
 	Download       int     `json:"download"`
-	Timing         int     `json:"timing"`
+	Timing         int64   `json:"timing"`
 	MediaType      int     `json:"media_type"`
-	Images         []Image `json:"images"`
+	Images         []Image `json:"images,omitempty"`
+	VideoID        string  `json:"video_id,omitempty"`
 	CreationID     string  `json:"creation_id"`
 }
 
@@ -294,11 +281,15 @@ type Image struct {
 
 // Cover represents the cover of the post
 type Cover struct {
-	Poster string `json:"poster"`
+	Poster      string `json:"poster"`
+	PosterDelay int    `json:"poster_delay,omitempty"`
 }
 
-// Anchor is an empty struct for now
-type Anchor struct{}
+// Anchor 作品挂载的位置信息
+type Anchor struct {
+	PoiID   string `json:"poi_id,omitempty"`
+	PoiName string `json:"poi_name,omitempty"`
+}
 
 // Declare contains declaration info
 type Declare struct {
@@ -316,71 +307,40 @@ type CreatePostResponse struct {
 }
 
 // createPost creates a new post (aweme)
-func (c *DyClient) createPost(ctx context.Context, commitResp *CommitUploadResponse, title string) (*CreatePostResponse, error) {
-	// TODO: msToken and a_bogus need to be generated dynamically
+func (c *DyClient) createPost(ctx context.Context, commitResp *CommitUploadResponse, title, description string, opts *PublishOptions) (*CreatePostResponse, error) {
 	imageInfo := commitResp.Result.PluginResult[0]
 
-	// Generate a creation_id, it seems to be a timestamp-based unique id.
-	creationID := fmt.Sprintf("gemini%d", time.Now().UnixNano()/1e6)
-
-	textExtra := fmt.Sprintf(`[{"start":0,"end":%d,"hashtag_id":0,"hashtag_name":"","type":7}]`, len(title))
-
-	payload := CreatePostRequest{
-		Item: Item{
-			Common: Common{
-				Text:           title,
-				TextExtra:      textExtra,
-				Activity:       "[]",
-				Challenges:     "[]",
-				HashtagSource:  "",
-				Mentions:       "[]",
-				VisibilityType: 0,
-				Download:       1,
-				Timing:         -1,
-				MediaType:      2, // 2 for images
-				Images: []Image{
-					{
-						URI:    imageInfo.ImageUri,
-						Width:  imageInfo.ImageWidth,
-						Height: imageInfo.ImageHeight,
-					},
-				},
-				CreationID: creationID,
-			},
-			Cover: Cover{
-				Poster: imageInfo.ImageUri,
-			},
-			Anchor:  Anchor{},
-			Declare: Declare{
-				UserDeclareInfo: "{}",
-			},
+	item, err := c.buildItem(ctx, title, description, opts)
+	if err != nil {
+		return nil, err
+	}
+	item.Common.MediaType = 2 // 2 for images
+	item.Common.Images = []Image{
+		{
+			URI:    imageInfo.ImageUri,
+			Width:  imageInfo.ImageWidth,
+			Height: imageInfo.ImageHeight,
 		},
 	}
-
-	// Query params from the captured request
-	queryParams := map[string]string{
-		"read_aid":         "2906",
-		"cookie_enabled":   "true",
-		"screen_width":     "2048",
-		"screen_height":    "1152",
-		"browser_language": "zh-CN",
-		"browser_platform": "Win32",
-		"browser_name":     "Mozilla",
-		"browser_version":  "5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36 Edg/139.0.0.0",
-		"browser_online":   "true",
-		"timezone_name":    "Asia/Shanghai",
-		"aid":              "1128",
-		"support_h265":     "1",
-		// "msToken": "...", // TODO: Needs to be generated dynamically
-		// "a_bogus": "...", // TODO: Needs to be generated dynamically
+	item.Cover = Cover{
+		Poster: imageInfo.ImageUri,
 	}
 
+	return c.submitPost(ctx, CreatePostRequest{Item: item}, "https://creator.douyin.com/creator-micro/content/post/image?default-tab=3&enter_from=publish_page&media_type=image&type=new")
+}
+
+// submitPost 调用 create_v2 接口提交作品
+func (c *DyClient) submitPost(ctx context.Context, payload CreatePostRequest, referer string) (*CreatePostResponse, error) {
+	queryParams := creatorQueryParams(map[string]string{
+		"read_aid": "2906",
+	})
+
 	resp, err := c.client.R().
 		SetContext(ctx).
 		SetResult(&CreatePostResponse{}).
 		SetBody(payload).
 		SetHeaders(map[string]string{
-			"Referer":      "https://creator.douyin.com/creator-micro/content/post/image?default-tab=3&enter_from=publish_page&media_type=image&type=new",
+			"Referer":      referer,
 			"Content-Type": "application/json",
 		}).
 		SetQueryParams(queryParams).
@@ -399,7 +359,9 @@ func (c *DyClient) createPost(ctx context.Context, commitResp *CommitUploadRespo
 }
 
 // PublishImage 发布图文
-func (c *DyClient) PublishImage(ctx context.Context, filePath, title, description string) (*CreatePostResponse, error) {
+// title 为作品标题，description 为标题之后的正文，可为空
+// opts: 话题、@用户、可见性等发布选项，可为 nil
+func (c *DyClient) PublishImage(ctx context.Context, filePath, title, description string, opts *PublishOptions) (*CreatePostResponse, error) {
 	// 1. 获取上传凭证
 	auth, err := c.getUploadAuth(ctx)
 	if err != nil {
@@ -427,7 +389,7 @@ func (c *DyClient) PublishImage(ctx context.Context, filePath, title, descriptio
 	}
 
 	// 5. 发布作品
-	createResp, err := c.createPost(ctx, commitResp, title)
+	createResp, err := c.createPost(ctx, commitResp, title, description, opts)
 	if err != nil {
 		return nil, fmt.Errorf("step 5: create post failed: %w", err)
 	}
@@ -0,0 +1,395 @@
+package douyin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	vodURL = "https://vod.bytedanceapi.com"
+
+	// defaultPartSize 分片上传的默认分片大小 (5MB)，小于该值的文件直接整体上传
+	defaultPartSize int64 = 5 * 1024 * 1024
+)
+
+// ProgressFunc 上传进度回调，uploaded 为已上传字节数，total 为文件总字节数
+type ProgressFunc func(uploaded, total int64)
+
+// VideoPublishOptions 发布视频的选项
+type VideoPublishOptions struct {
+	PublishOptions
+
+	// Title 作品标题/描述文本
+	Title string
+	// CoverPath 自定义封面图片路径，为空时使用 CoverTime 处的视频帧作为封面
+	CoverPath string
+	// CoverTime 选取视频封面帧的时间点 (秒)
+	CoverTime float64
+	// PartSize 分片大小，为 0 时使用 defaultPartSize
+	PartSize int64
+	// Progress 上传进度回调，可为空
+	Progress ProgressFunc
+}
+
+// ApplyUploadInnerResponse 申请视频上传的响应
+type ApplyUploadInnerResponse struct {
+	ResponseMetadata struct {
+		RequestID string `json:"RequestId"`
+		Action    string `json:"Action"`
+		Version   string `json:"Version"`
+		Service   string `json:"Service"`
+		Error     *struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Error,omitempty"`
+	} `json:"ResponseMetadata"`
+	Result struct {
+		InnerUploadAddress struct {
+			UploadNodes []UploadNode `json:"UploadNodes"`
+		} `json:"InnerUploadAddress"`
+	} `json:"Result"`
+}
+
+// UploadNode 视频上传节点
+type UploadNode struct {
+	Vid        string         `json:"Vid"`
+	StoreInfos []VodStoreInfo `json:"StoreInfos"`
+	UploadHost string         `json:"UploadHost"`
+	SessionKey string         `json:"SessionKey"`
+}
+
+// VodStoreInfo 视频存储信息
+type VodStoreInfo struct {
+	StoreUri string `json:"StoreUri"`
+	Auth     string `json:"Auth"`
+	UploadID string `json:"UploadID"`
+}
+
+// CommitUploadInnerResponse 确认视频上传的响应
+type CommitUploadInnerResponse struct {
+	ResponseMetadata struct {
+		RequestID string `json:"RequestId"`
+		Action    string `json:"Action"`
+		Version   string `json:"Version"`
+		Service   string `json:"Service"`
+	} `json:"ResponseMetadata"`
+	Result struct {
+		Results []VideoCommitResult `json:"Results"`
+	} `json:"Result"`
+}
+
+// VideoCommitResult 确认视频上传的结果
+type VideoCommitResult struct {
+	Vid       string    `json:"Vid"`
+	VideoMeta VideoMeta `json:"VideoMeta"`
+	PosterUri string    `json:"PosterUri"`
+}
+
+// VideoMeta 视频元信息
+type VideoMeta struct {
+	Uri      string  `json:"Uri"`
+	Height   int     `json:"Height"`
+	Width    int     `json:"Width"`
+	Duration float64 `json:"Duration"`
+	Bitrate  int     `json:"Bitrate"`
+	Md5      string  `json:"Md5"`
+	Format   string  `json:"Format"`
+	Size     int64   `json:"Size"`
+	Codec    string  `json:"Codec"`
+}
+
+// tosResponse TOS 分片上传接口的响应
+type tosResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		UploadID string `json:"uploadid"`
+		Crc32    string `json:"crc32"`
+	} `json:"data"`
+}
+
+// applyVideoUpload 申请视频上传
+func (c *DyClient) applyVideoUpload(ctx context.Context, fileSize int64) (*UploadNode, error) {
+	reqURL := fmt.Sprintf("%s/?Action=ApplyUploadInner&Version=2020-11-19&SpaceName=aweme&FileType=video&IsInner=1&FileSize=%d&app_id=2906&user_id=&s=%s",
+		vodURL, fileSize, randomString(11))
+
+	resp, err := c.vodClient.R().
+		SetResult(&ApplyUploadInnerResponse{}).
+		SetContext(ctx).
+		Get(reqURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	applyResp := resp.Result().(*ApplyUploadInnerResponse)
+	if e := applyResp.ResponseMetadata.Error; e != nil {
+		return nil, fmt.Errorf("apply video upload failed, code: %s, message: %s", e.Code, e.Message)
+	}
+	nodes := applyResp.Result.InnerUploadAddress.UploadNodes
+	if len(nodes) == 0 || len(nodes[0].StoreInfos) == 0 {
+		return nil, fmt.Errorf("apply video upload failed, no upload node returned: %s", resp.String())
+	}
+	return &nodes[0], nil
+}
+
+// uploadVideoToTOS 上传视频到TOS，大于分片大小的文件使用分片上传
+func (c *DyClient) uploadVideoToTOS(ctx context.Context, node *UploadNode, filePath string, partSize int64, progress ProgressFunc) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	total := stat.Size()
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+
+	store := node.StoreInfos[0]
+	uploadURL := "https://" + node.UploadHost + "/upload/v1/" + store.StoreUri
+
+	if total <= partSize {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		if _, err := c.postTOS(ctx, uploadURL, store.Auth, data); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(total, total)
+		}
+		return nil
+	}
+
+	// 1. 初始化分片上传
+	initResp, err := c.postTOS(ctx, uploadURL+"?uploads", store.Auth, nil)
+	if err != nil {
+		return fmt.Errorf("init multipart upload failed: %w", err)
+	}
+	uploadID := initResp.Data.UploadID
+	if uploadID == "" {
+		uploadID = store.UploadID
+	}
+
+	// 2. 逐片上传
+	var parts []string
+	buf := make([]byte, partSize)
+	var uploaded int64
+	for partNumber := 1; uploaded < total; partNumber++ {
+		n, err := io.ReadFull(f, buf)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		partURL := fmt.Sprintf("%s?partNumber=%d&uploadID=%s", uploadURL, partNumber, uploadID)
+		if _, err := c.postTOS(ctx, partURL, store.Auth, buf[:n]); err != nil {
+			return fmt.Errorf("upload part %d failed: %w", partNumber, err)
+		}
+		parts = append(parts, fmt.Sprintf("%d:%s", partNumber, tosCRC32(buf[:n])))
+
+		uploaded += int64(n)
+		if progress != nil {
+			progress(uploaded, total)
+		}
+	}
+
+	// 3. 合并分片
+	finishURL := fmt.Sprintf("%s?uploadID=%s", uploadURL, uploadID)
+	resp, err := c.uploadClient.R().
+		SetContext(ctx).
+		SetHeaders(map[string]string{
+			"Content-Type":  "text/plain;charset=UTF-8",
+			"Authorization": store.Auth,
+		}).
+		SetBody(strings.Join(parts, ",")).
+		Post(finishURL)
+	if err != nil {
+		return fmt.Errorf("finish multipart upload failed: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("finish multipart upload failed, status code: %d, body: %s", resp.StatusCode(), resp.String())
+	}
+	return nil
+}
+
+// postTOS 向TOS发送一段数据，并在请求头中附带其 CRC32
+func (c *DyClient) postTOS(ctx context.Context, reqURL, auth string, data []byte) (*tosResponse, error) {
+	resp, err := c.uploadClient.R().
+		SetContext(ctx).
+		SetHeaders(map[string]string{
+			"Content-CRC32":       tosCRC32(data),
+			"Content-Type":        "application/octet-stream",
+			"Content-Disposition": `attachment; filename="undefined"`,
+			"Authorization":       auth,
+		}).
+		SetBody(data).
+		SetResult(&tosResponse{}).
+		Post(reqURL)
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("upload to TOS failed, status code: %d, body: %s", resp.StatusCode(), resp.String())
+	}
+
+	tosResp := resp.Result().(*tosResponse)
+	if tosResp.Code != 0 && tosResp.Code != 2000 {
+		return nil, fmt.Errorf("upload to TOS failed, code: %d, message: %s", tosResp.Code, tosResp.Message)
+	}
+	return tosResp, nil
+}
+
+// tosCRC32 计算TOS上传所需的 CRC32 (十六进制)
+func tosCRC32(data []byte) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE(data))
+}
+
+// commitVideoUpload 确认视频上传，同时获取视频元信息并按 coverTime 截取封面
+func (c *DyClient) commitVideoUpload(ctx context.Context, node *UploadNode, coverTime float64) (*VideoCommitResult, error) {
+	reqURL := fmt.Sprintf("%s/?Action=CommitUploadInner&Version=2020-11-19&SpaceName=aweme", vodURL)
+
+	payload := map[string]interface{}{
+		"SessionKey": node.SessionKey,
+		"Functions": []map[string]interface{}{
+			{"name": "GetMeta"},
+			{"name": "Snapshot", "input": map[string]interface{}{"SnapshotTime": coverTime}},
+		},
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := sha256.Sum256(payloadBytes)
+
+	resp, err := c.vodClient.R().
+		SetContext(ctx).
+		SetHeaders(map[string]string{
+			"Content-Type":         "application/json",
+			"X-Amz-Content-Sha256": hex.EncodeToString(payloadHash[:]),
+		}).
+		SetBody(payloadBytes).
+		SetResult(&CommitUploadInnerResponse{}).
+		Post(reqURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	commitResp := resp.Result().(*CommitUploadInnerResponse)
+	if len(commitResp.Result.Results) == 0 {
+		return nil, fmt.Errorf("commit video upload failed: %s", resp.String())
+	}
+	return &commitResp.Result.Results[0], nil
+}
+
+// uploadImage 通过 ImageX 上传单张图片 (视频封面、私信图片等)
+func (c *DyClient) uploadImage(ctx context.Context, auth *AuthDetails, imagePath string) (*PluginResult, error) {
+	applyResp, err := c.applyImageUpload(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.uploadFileToTOS(ctx, applyResp, imagePath); err != nil {
+		return nil, err
+	}
+	commitResp, err := c.commitImageUpload(ctx, auth, applyResp)
+	if err != nil {
+		return nil, err
+	}
+	if len(commitResp.Result.PluginResult) == 0 {
+		return nil, fmt.Errorf("commit image upload returned no image")
+	}
+	return &commitResp.Result.PluginResult[0], nil
+}
+
+// PublishVideo 发布视频
+func (c *DyClient) PublishVideo(ctx context.Context, videoPath string, opts *VideoPublishOptions) (*CreatePostResponse, error) {
+	if opts == nil {
+		opts = &VideoPublishOptions{}
+	}
+	stat, err := os.Stat(videoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 获取上传凭证
+	auth, err := c.getUploadAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("step 1: get upload auth failed: %w", err)
+	}
+
+	ctx = context.WithValue(ctx, "auth", auth)
+
+	// 2. 申请上传
+	node, err := c.applyVideoUpload(ctx, stat.Size())
+	if err != nil {
+		return nil, fmt.Errorf("step 2: apply video upload failed: %w", err)
+	}
+
+	// 3. 分片上传文件
+	err = c.uploadVideoToTOS(ctx, node, videoPath, opts.PartSize, opts.Progress)
+	if err != nil {
+		return nil, fmt.Errorf("step 3: upload video to TOS failed: %w", err)
+	}
+
+	// 4. 确认上传
+	video, err := c.commitVideoUpload(ctx, node, opts.CoverTime)
+	if err != nil {
+		return nil, fmt.Errorf("step 4: commit video upload failed: %w", err)
+	}
+
+	// 5. 封面
+	cover := Cover{
+		Poster:      video.PosterUri,
+		PosterDelay: int(opts.CoverTime * 1000),
+	}
+	if opts.CoverPath != "" {
+		img, err := c.uploadImage(ctx, auth, opts.CoverPath)
+		if err != nil {
+			return nil, fmt.Errorf("step 5: upload cover failed: %w", err)
+		}
+		cover.Poster = img.ImageUri
+		cover.PosterDelay = 0
+	}
+
+	// 6. 发布作品
+	item, err := c.buildItem(ctx, opts.Title, "", &opts.PublishOptions)
+	if err != nil {
+		return nil, fmt.Errorf("step 6: build post failed: %w", err)
+	}
+	item.Common.MediaType = 4 // 4 for video
+	item.Common.VideoID = video.Vid
+	item.Cover = cover
+
+	payload := CreatePostRequest{Item: item}
+	createResp, err := c.submitPost(ctx, payload, "https://creator.douyin.com/creator-micro/content/post/video?enter_from=publish_page")
+	if err != nil {
+		return nil, fmt.Errorf("step 6: create post failed: %w", err)
+	}
+
+	return createResp, nil
+}
+
+// randomString 生成指定长度的小写字母数字随机串
+func randomString(n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rand.Intn(len(letters))]
+	}
+	return string(b)
+}
@@ -0,0 +1,282 @@
+package douyin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"resty.dev/v3"
+)
+
+// tosRequest TOS 服务端收到的一次请求
+type tosRequest struct {
+	query string
+	crc   string
+	auth  string
+	body  []byte
+}
+
+// tosServer 模拟 TOS 上传节点，fail 返回非空时以该状态码和响应体应答
+type tosServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []tosRequest
+	uploadID string
+	fail     func(query string) (int, string)
+}
+
+func newTOSServer(t *testing.T) *tosServer {
+	t.Helper()
+	s := &tosServer{uploadID: "uid-1"}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/upload/v1/tos-cn-i/video" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, tosRequest{r.URL.RawQuery, r.Header.Get("Content-CRC32"), r.Header.Get("Authorization"), body})
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if s.fail != nil {
+			if status, msg := s.fail(r.URL.RawQuery); status != 0 {
+				w.WriteHeader(status)
+				io.WriteString(w, msg)
+				return
+			}
+		}
+		if r.URL.RawQuery == "uploads" {
+			fmt.Fprintf(w, `{"code":2000,"data":{"uploadid":%q}}`, s.uploadID)
+			return
+		}
+		fmt.Fprintf(w, `{"code":2000,"data":{"crc32":%q}}`, tosCRC32(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tosServer) client() *DyClient {
+	return &DyClient{uploadClient: resty.New().SetTransport(s.Client().Transport)}
+}
+
+func (s *tosServer) node() *UploadNode {
+	return &UploadNode{
+		UploadHost: strings.TrimPrefix(s.URL, "https://"),
+		StoreInfos: []VodStoreInfo{{StoreUri: "tos-cn-i/video", Auth: "tos-auth", UploadID: "store-uid"}},
+	}
+}
+
+func writeVideo(t *testing.T, size int) (string, []byte) {
+	t.Helper()
+	data := bytes.Repeat([]byte("abcdefghij"), size/10+1)[:size]
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, data
+}
+
+func TestUploadVideoToTOS(t *testing.T) {
+	const partSize = 10
+	tests := []struct {
+		name      string
+		size      int
+		wantParts []int
+	}{
+		{"smaller than part size", 7, nil},
+		{"exactly one part", 10, nil},
+		{"exact multiple of part size", 20, []int{10, 10}},
+		{"last part shorter", 25, []int{10, 10, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTOSServer(t)
+			path, data := writeVideo(t, tt.size)
+			var progress []int64
+			err := srv.client().uploadVideoToTOS(context.Background(), srv.node(), path, partSize, func(uploaded, total int64) {
+				if total != int64(tt.size) {
+					t.Errorf("progress total = %d, want %d", total, tt.size)
+				}
+				progress = append(progress, uploaded)
+			})
+			if err != nil {
+				t.Fatalf("uploadVideoToTOS() error = %v", err)
+			}
+			for _, r := range srv.requests {
+				if r.auth != "tos-auth" {
+					t.Errorf("request %q Authorization = %q", r.query, r.auth)
+				}
+			}
+
+			if tt.wantParts == nil {
+				if len(srv.requests) != 1 || srv.requests[0].query != "" || !bytes.Equal(srv.requests[0].body, data) || srv.requests[0].crc != tosCRC32(data) {
+					t.Fatalf("single upload requests = %+v", srv.requests)
+				}
+				if len(progress) != 1 || progress[0] != int64(tt.size) {
+					t.Errorf("progress = %v", progress)
+				}
+				return
+			}
+
+			// ?uploads 初始化 + 每片一次 + 合并
+			if len(srv.requests) != len(tt.wantParts)+2 {
+				t.Fatalf("got %d requests, want %d", len(srv.requests), len(tt.wantParts)+2)
+			}
+			if srv.requests[0].query != "uploads" || len(srv.requests[0].body) != 0 {
+				t.Errorf("init request = %+v", srv.requests[0])
+			}
+			var (
+				offset int
+				crcs   []string
+				want   []int64
+			)
+			for i, n := range tt.wantParts {
+				r := srv.requests[i+1]
+				chunk := data[offset : offset+n]
+				offset += n
+				if wantQuery := fmt.Sprintf("partNumber=%d&uploadID=uid-1", i+1); r.query != wantQuery {
+					t.Errorf("part %d query = %q, want %q", i+1, r.query, wantQuery)
+				}
+				if !bytes.Equal(r.body, chunk) || r.crc != tosCRC32(chunk) {
+					t.Errorf("part %d body = %q crc = %s, want %q", i+1, r.body, r.crc, chunk)
+				}
+				crcs = append(crcs, fmt.Sprintf("%d:%s", i+1, tosCRC32(chunk)))
+				want = append(want, int64(offset))
+			}
+			finish := srv.requests[len(srv.requests)-1]
+			if finish.query != "uploadID=uid-1" || string(finish.body) != strings.Join(crcs, ",") {
+				t.Errorf("finish request query = %q body = %q, want body %q", finish.query, finish.body, strings.Join(crcs, ","))
+			}
+			if fmt.Sprint(progress) != fmt.Sprint(want) {
+				t.Errorf("progress = %v, want %v", progress, want)
+			}
+		})
+	}
+}
+
+func TestUploadVideoToTOSFallbackUploadID(t *testing.T) {
+	srv := newTOSServer(t)
+	srv.uploadID = ""
+	path, _ := writeVideo(t, 15)
+	if err := srv.client().uploadVideoToTOS(context.Background(), srv.node(), path, 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.requests[1].query; got != "partNumber=1&uploadID=store-uid" {
+		t.Errorf("part query = %q, want the store UploadID", got)
+	}
+}
+
+func TestUploadVideoToTOSErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fail     func(query string) (int, string)
+		wantErr  string
+		requests int
+	}{
+		{
+			name: "init rejected",
+			fail: func(q string) (int, string) {
+				if q == "uploads" {
+					return http.StatusForbidden, "denied"
+				}
+				return 0, ""
+			},
+			wantErr:  "init multipart upload failed",
+			requests: 1,
+		},
+		{
+			name: "part http error",
+			fail: func(q string) (int, string) {
+				if strings.HasPrefix(q, "partNumber=2&") {
+					return http.StatusInternalServerError, "oops"
+				}
+				return 0, ""
+			},
+			wantErr:  "upload part 2 failed",
+			requests: 3,
+		},
+		{
+			name: "part error code",
+			fail: func(q string) (int, string) {
+				if strings.HasPrefix(q, "partNumber=1&") {
+					return http.StatusOK, `{"code":4024,"message":"crc mismatch"}`
+				}
+				return 0, ""
+			},
+			wantErr:  "upload part 1 failed",
+			requests: 2,
+		},
+		{
+			name: "finish rejected",
+			fail: func(q string) (int, string) {
+				if q == "uploadID=uid-1" {
+					return http.StatusBadRequest, "bad parts"
+				}
+				return 0, ""
+			},
+			wantErr:  "finish multipart upload failed, status code: 400",
+			requests: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTOSServer(t)
+			srv.fail = tt.fail
+			path, _ := writeVideo(t, 25)
+			err := srv.client().uploadVideoToTOS(context.Background(), srv.node(), path, 10, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("uploadVideoToTOS() error = %v, want %q", err, tt.wantErr)
+			}
+			if len(srv.requests) != tt.requests {
+				t.Errorf("got %d requests, want %d (upload should stop at the failure)", len(srv.requests), tt.requests)
+			}
+		})
+	}
+}
+
+func TestCommitVideoUpload(t *testing.T) {
+	var payload struct {
+		SessionKey string
+		Functions  []struct {
+			Name  string                 `json:"name"`
+			Input map[string]interface{} `json:"input"`
+		}
+	}
+	respBody := `{"Result":{"Results":[{"Vid":"v0200","PosterUri":"tos-cn-p/poster","VideoMeta":{"Width":1080,"Height":1920,"Duration":12.5}}]}}`
+	c := New("msToken=test", nil)
+	c.vodClient.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Query().Get("Action") != "CommitUploadInner" || r.Header.Get("Authorization") == "" {
+			t.Errorf("commit request = %s, Authorization = %q", r.URL, r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			return nil, err
+		}
+		return jsonResponse(r, respBody), nil
+	}))
+	ctx := context.WithValue(context.Background(), "auth", &AuthDetails{AccessKeyID: "ak", SecretAccessKey: "sk", SessionToken: "st"})
+
+	video, err := c.commitVideoUpload(ctx, &UploadNode{SessionKey: "session"}, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if video.Vid != "v0200" || video.PosterUri != "tos-cn-p/poster" || video.VideoMeta.Height != 1920 {
+		t.Errorf("commitVideoUpload() = %+v", video)
+	}
+	if payload.SessionKey != "session" || len(payload.Functions) != 2 || payload.Functions[1].Name != "Snapshot" || payload.Functions[1].Input["SnapshotTime"] != 1.5 {
+		t.Errorf("commit payload = %+v", payload)
+	}
+
+	respBody = `{"ResponseMetadata":{"Error":{"Code":"InvalidSession"}},"Result":{}}`
+	if _, err := c.commitVideoUpload(ctx, &UploadNode{SessionKey: "expired"}, 0); err == nil {
+		t.Error("commitVideoUpload() expected error when no result is returned")
+	}
+}
@@ -0,0 +1,170 @@
+package xhs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// CommentUser 评论作者或被 @ 的用户
+type CommentUser struct {
+	UserID    string `json:"user_id"`
+	Nickname  string `json:"nickname"`
+	Image     string `json:"image"`
+	XsecToken string `json:"xsec_token"`
+}
+
+// Comment 笔记评论，数量字段为接口返回的展示文本
+type Comment struct {
+	ID              string        `json:"id"`
+	NoteID          string        `json:"note_id"`
+	Content         string        `json:"content"`
+	User            CommentUser   `json:"user_info"`
+	LikeCount       string        `json:"like_count"`
+	Liked           bool          `json:"liked"`
+	CreateTime      int64         `json:"create_time"`
+	IPLocation      string        `json:"ip_location"`
+	Pictures        []NoteImage   `json:"pictures"`
+	AtUsers         []CommentUser `json:"at_users"`
+	SubCommentCount string        `json:"sub_comment_count"`
+	// SubComments 一级评论下随列表返回的前几条回复，完整回复需通过 ListSubComments 获取
+	SubComments []Comment `json:"sub_comments"`
+	// TargetComment 回复所针对的评论，仅回复中存在
+	TargetComment *struct {
+		ID   string      `json:"id"`
+		User CommentUser `json:"user_info"`
+	} `json:"target_comment"`
+}
+
+// CommentIterator 以 cursor 游标遍历评论或回复
+//
+//	it, err := client.ListComments(ctx, noteID, xsecToken)
+//	if err != nil {
+//		...
+//	}
+//	for it.HasMore() {
+//		comments, err := it.Next(ctx)
+//		...
+//	}
+type CommentIterator struct {
+	c             *XhsClient
+	path          string
+	noteID        string
+	rootCommentID string
+	xsecToken     string
+
+	cursor  string
+	hasMore bool
+
+	// first 构造迭代器时已获取的第一页，由第一次 Next 返回
+	first      []Comment
+	firstReady bool
+}
+
+// ListComments 获取笔记一级评论的第一页并返回迭代器
+// xsecToken 为空时使用缓存的值，非空时写入缓存，供之后的 ListSubComments 使用
+func (c *XhsClient) ListComments(ctx context.Context, noteID, xsecToken string) (*CommentIterator, error) {
+	if xsecToken == "" {
+		xsecToken = c.xsecToken(noteID)
+	} else {
+		c.setXsecToken(noteID, xsecToken)
+	}
+	return c.listComments(ctx, &CommentIterator{
+		c:         c,
+		path:      "/api/sns/web/v2/comment/page",
+		noteID:    noteID,
+		xsecToken: xsecToken,
+		hasMore:   true,
+	})
+}
+
+// ListSubComments 获取一级评论下回复的第一页并返回迭代器，使用缓存的笔记 xsec_token
+func (c *XhsClient) ListSubComments(ctx context.Context, noteID, rootCommentID string) (*CommentIterator, error) {
+	return c.listComments(ctx, &CommentIterator{
+		c:             c,
+		path:          "/api/sns/web/v2/comment/sub/page",
+		noteID:        noteID,
+		rootCommentID: rootCommentID,
+		xsecToken:     c.xsecToken(noteID),
+		hasMore:       true,
+	})
+}
+
+func (c *XhsClient) listComments(ctx context.Context, it *CommentIterator) (*CommentIterator, error) {
+	comments, err := it.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	it.first, it.firstReady = comments, true
+	return it, nil
+}
+
+// HasMore 是否还有下一页
+func (it *CommentIterator) HasMore() bool {
+	return it.firstReady || it.hasMore
+}
+
+// Next 获取下一页评论
+func (it *CommentIterator) Next(ctx context.Context) ([]Comment, error) {
+	if it.firstReady {
+		comments := it.first
+		it.first, it.firstReady = nil, false
+		return comments, nil
+	}
+	if !it.hasMore {
+		return nil, nil
+	}
+	return it.fetch(ctx)
+}
+
+// fetch 请求 cursor 之后的一页评论并更新游标
+func (it *CommentIterator) fetch(ctx context.Context) ([]Comment, error) {
+	params := map[string]string{
+		"note_id":        it.noteID,
+		"cursor":         it.cursor,
+		"top_comment_id": "",
+		"image_formats":  "jpg,webp,avif",
+		"xsec_token":     it.xsecToken,
+	}
+	if it.rootCommentID != "" {
+		params["root_comment_id"] = it.rootCommentID
+		params["num"] = strconv.Itoa(10)
+	}
+
+	resp, err := it.c.client.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		Get(apiHost + it.path)
+	if err != nil {
+		return nil, fmt.Errorf("请求评论API失败: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("请求评论API返回错误状态: %s, body: %s", resp.Status(), resp.String())
+	}
+
+	var result struct {
+		BaseResponse
+		Data struct {
+			Comments []Comment `json:"comments"`
+			Cursor   string    `json:"cursor"`
+			HasMore  bool      `json:"has_more"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(resp.String()), &result); err != nil {
+		return nil, fmt.Errorf("解析评论API响应失败: %w", err)
+	}
+	if err := result.check("list comments"); err != nil {
+		return nil, err
+	}
+
+	for _, cm := range result.Data.Comments {
+		it.c.setXsecToken(cm.User.UserID, cm.User.XsecToken)
+		for _, sub := range cm.SubComments {
+			it.c.setXsecToken(sub.User.UserID, sub.User.XsecToken)
+		}
+	}
+	it.cursor = result.Data.Cursor
+	it.hasMore = result.Data.HasMore && it.cursor != ""
+	return result.Data.Comments, nil
+}
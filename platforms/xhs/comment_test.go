@@ -0,0 +1,85 @@
+package xhs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// jsonResponse 构造 roundTripFunc 返回的 JSON 响应
+func jsonResponse(r *http.Request, body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}
+}
+
+func TestListCommentsCachesXsecToken(t *testing.T) {
+	var requests []*http.Request
+	c := New("a1=test; webId=test")
+	c.client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r)
+		if strings.HasSuffix(r.URL.Path, "/comment/sub/page") {
+			return jsonResponse(r, `{"success":true,"data":{"comments":[{"id":"s1"}],"cursor":"","has_more":false}}`), nil
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			return jsonResponse(r, `{"success":true,"data":{"comments":[{"id":"c1","user_info":{"user_id":"u1","xsec_token":"ut"}}],"cursor":"next","has_more":true}}`), nil
+		}
+		return jsonResponse(r, `{"success":true,"data":{"comments":[{"id":"c2"}],"cursor":"","has_more":false}}`), nil
+	}))
+	ctx := context.Background()
+
+	it, err := c.ListComments(ctx, "note1", "token1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("ListComments() sent %d requests, want the first page only", len(requests))
+	}
+	var ids []string
+	for it.HasMore() {
+		comments, err := it.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cm := range comments {
+			ids = append(ids, cm.ID)
+		}
+	}
+	if strings.Join(ids, ",") != "c1,c2" {
+		t.Errorf("comments = %v, want c1,c2", ids)
+	}
+	if got := requests[1].URL.Query().Get("cursor"); got != "next" {
+		t.Errorf("second page cursor = %q, want next", got)
+	}
+	if got := c.xsecToken("u1"); got != "ut" {
+		t.Errorf("author xsec_token = %q, want ut", got)
+	}
+
+	// 回复列表只读取缓存，需能使用 ListComments 显式传入的 xsec_token
+	sub, err := c.ListSubComments(ctx, "note1", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := requests[len(requests)-1].URL.Query()
+	if q.Get("xsec_token") != "token1" || q.Get("root_comment_id") != "c1" {
+		t.Errorf("sub comment query = %v", q)
+	}
+	if comments, _ := sub.Next(ctx); len(comments) != 1 || sub.HasMore() {
+		t.Errorf("sub comments = %+v, HasMore = %v", comments, sub.HasMore())
+	}
+}
+
+func TestListCommentsError(t *testing.T) {
+	c := New("a1=test; webId=test")
+	c.client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return jsonResponse(r, `{"success":false,"code":-100,"msg":"登录已过期"}`), nil
+	}))
+	if _, err := c.ListComments(context.Background(), "note1", "token1"); err == nil {
+		t.Fatal("ListComments() expected error")
+	}
+}
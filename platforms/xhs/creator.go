@@ -0,0 +1,149 @@
+package xhs
+
+import (
+	"context"
+	"strconv"
+)
+
+const creatorNoteManagerReferer = creatorHost + "/new/note-manager"
+
+// CreatorNoteStatus 笔记管理页的审核状态，与页面上的 全部/已发布/审核中/未通过 四个 tab 一一对应
+// 列表接口的 tab 参数与笔记的 tab_status 字段使用同一组取值
+type CreatorNoteStatus int
+
+const (
+	// CreatorNoteAll "全部" tab，只作为 ListCreatorNotes 的筛选条件，不会出现在笔记上
+	CreatorNoteAll CreatorNoteStatus = 0
+	// CreatorNotePublished 审核通过，已公开发布
+	CreatorNotePublished CreatorNoteStatus = 1
+	// CreatorNoteReviewing 刚发布或编辑后等待审核，暂时仅自己可见
+	CreatorNoteReviewing CreatorNoteStatus = 2
+	// CreatorNoteRejected 审核未通过，原因见 CreatorNote.PermissionMsg
+	CreatorNoteRejected CreatorNoteStatus = 3
+)
+
+// String 返回笔记管理页上对应 tab 的名称
+func (s CreatorNoteStatus) String() string {
+	switch s {
+	case CreatorNoteAll:
+		return "全部"
+	case CreatorNotePublished:
+		return "已发布"
+	case CreatorNoteReviewing:
+		return "审核中"
+	case CreatorNoteRejected:
+		return "未通过"
+	default:
+		return "CreatorNoteStatus(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// CreatorNote 创作者中心笔记列表中的一篇笔记
+type CreatorNote struct {
+	ID             string `json:"id"`
+	DisplayTitle   string `json:"display_title"`
+	Type           string `json:"type"`
+	Time           string `json:"time"`
+	XsecToken      string `json:"xsec_token"`
+	TabStatus      int    `json:"tab_status"`
+	PermissionCode int    `json:"permission_code"`
+	PermissionMsg  string `json:"permission_msg"`
+	Level          int    `json:"level"`
+	Sticky         bool   `json:"sticky"`
+	Images         []struct {
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"images_list"`
+	ViewCount      int64 `json:"view_count"`
+	Likes          int64 `json:"likes"`
+	CollectedCount int64 `json:"collected_count"`
+	CommentsCount  int64 `json:"comments_count"`
+	SharedCount    int64 `json:"shared_count"`
+}
+
+// Status 返回笔记的审核状态
+func (n *CreatorNote) Status() CreatorNoteStatus {
+	return CreatorNoteStatus(n.TabStatus)
+}
+
+// Restricted 笔记已发布但被限制展示 (如仅自己可见)，PermissionMsg 为页面上展示的原因
+func (n *CreatorNote) Restricted() bool {
+	return n.PermissionCode != 0
+}
+
+// CreatorNoteList 创作者中心笔记列表的一页
+type CreatorNoteList struct {
+	Notes []CreatorNote `json:"notes"`
+	// Page 下一页的页码，没有更多时为 -1
+	Page int `json:"page"`
+}
+
+// HasMore 是否还有下一页
+func (l *CreatorNoteList) HasMore() bool {
+	return l.Page >= 0
+}
+
+// NoteUpdate 编辑笔记时需要修改的字段，nil 表示保持不变
+type NoteUpdate struct {
+	Title *string
+	Desc  *string
+}
+
+// ListCreatorNotes 获取自己发布的笔记列表
+// status: 按审核状态筛选，CreatorNoteAll 表示全部
+// page: 页码，首页传 0，之后传上一页返回的 Page
+func (c *XhsClient) ListCreatorNotes(ctx context.Context, status CreatorNoteStatus, page int) (*CreatorNoteList, error) {
+	var result struct {
+		BaseResponse
+		Data CreatorNoteList `json:"data"`
+	}
+	err := c.getJSON(ctx, apiHost+"/web_api/sns/v5/creator/note/user/posted", creatorNoteManagerReferer, map[string]string{
+		"tab":  strconv.Itoa(int(status)),
+		"page": strconv.Itoa(page),
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("list creator notes"); err != nil {
+		return nil, err
+	}
+	for _, n := range result.Data.Notes {
+		c.setXsecToken(n.ID, n.XsecToken)
+	}
+	return &result.Data, nil
+}
+
+// EditNote 修改笔记的标题或正文
+func (c *XhsClient) EditNote(ctx context.Context, noteID string, update NoteUpdate) error {
+	body := map[string]interface{}{
+		"note_id": noteID,
+	}
+	if update.Title != nil {
+		body["title"] = *update.Title
+	}
+	if update.Desc != nil {
+		body["desc"] = *update.Desc
+	}
+	return c.postCreatorAction(ctx, "edit note", "/web_api/sns/v1/note/update", body)
+}
+
+// DeleteNote 删除笔记
+func (c *XhsClient) DeleteNote(ctx context.Context, noteID string) error {
+	return c.postCreatorAction(ctx, "delete note", "/web_api/sns/capa/postgw/note/delete", map[string]string{
+		"note_id": noteID,
+	})
+}
+
+// postCreatorAction 以笔记管理页的身份提交编辑、删除等请求，成功与否以 success 字段为准
+func (c *XhsClient) postCreatorAction(ctx context.Context, action, path string, body interface{}) error {
+	var result BaseResponse
+	err := c.postJSONWithHeaders(ctx, path, map[string]string{
+		"Origin":  creatorHost,
+		"Referer": creatorNoteManagerReferer,
+	}, body, &result)
+	if err != nil {
+		return err
+	}
+	return result.check(action)
+}
@@ -0,0 +1,136 @@
+package xhs
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+const creatorDataReferer = creatorHost + "/statistics/data-analysis"
+
+// NoteMetrics 单篇笔记的数据
+type NoteMetrics struct {
+	NoteID       string `json:"note_id"`
+	ViewCount    int64  `json:"view_count"`
+	LikeCount    int64  `json:"like_count"`
+	CollectCount int64  `json:"collect_count"`
+	CommentCount int64  `json:"comment_count"`
+	ShareCount   int64  `json:"share_count"`
+	// RiseFansCount 笔记带来的涨粉数
+	RiseFansCount int64 `json:"rise_fans_count"`
+	// AvgViewTime 平均观看时长，单位秒
+	AvgViewTime float64 `json:"avg_view_time"`
+}
+
+// DailyStats 账号某一天的数据
+type DailyStats struct {
+	Date     time.Time `json:"date"`
+	Views    int64     `json:"views"`
+	Likes    int64     `json:"likes"`
+	Collects int64     `json:"collects"`
+	Comments int64     `json:"comments"`
+	Shares   int64     `json:"shares"`
+	NewFans  int64     `json:"new_fans"`
+}
+
+// MetricRecord DailyStats 按指标拆开后的一行，Metric 取 views、likes、collects、comments、shares、new_fans
+type MetricRecord struct {
+	Date   time.Time `json:"date"`
+	Metric string    `json:"metric"`
+	Value  int64     `json:"value"`
+}
+
+// Records 将当天的六项指标拆成六条 MetricRecord
+func (s DailyStats) Records() []MetricRecord {
+	return []MetricRecord{
+		{Date: s.Date, Metric: "views", Value: s.Views},
+		{Date: s.Date, Metric: "likes", Value: s.Likes},
+		{Date: s.Date, Metric: "collects", Value: s.Collects},
+		{Date: s.Date, Metric: "comments", Value: s.Comments},
+		{Date: s.Date, Metric: "shares", Value: s.Shares},
+		{Date: s.Date, Metric: "new_fans", Value: s.NewFans},
+	}
+}
+
+// dailyCount 数据中心按天返回的计数，date 为当天零点的毫秒时间戳
+type dailyCount struct {
+	Date  int64 `json:"date"`
+	Count int64 `json:"count"`
+}
+
+// accountTrend 账号数据接口中各指标按天的列表
+type accountTrend struct {
+	ViewList     []dailyCount `json:"view_list"`
+	LikeList     []dailyCount `json:"like_list"`
+	CollectList  []dailyCount `json:"collect_list"`
+	CommentList  []dailyCount `json:"comment_list"`
+	ShareList    []dailyCount `json:"share_list"`
+	RiseFansList []dailyCount `json:"rise_fans_list"`
+}
+
+// dailyStats 将各指标的列表按日期合并，结果按日期升序排列
+func (t *accountTrend) dailyStats() []DailyStats {
+	days := map[int64]*DailyStats{}
+	merge := func(list []dailyCount, set func(*DailyStats, int64)) {
+		for _, d := range list {
+			s, ok := days[d.Date]
+			if !ok {
+				s = &DailyStats{Date: time.UnixMilli(d.Date)}
+				days[d.Date] = s
+			}
+			set(s, d.Count)
+		}
+	}
+	merge(t.ViewList, func(s *DailyStats, v int64) { s.Views = v })
+	merge(t.LikeList, func(s *DailyStats, v int64) { s.Likes = v })
+	merge(t.CollectList, func(s *DailyStats, v int64) { s.Collects = v })
+	merge(t.CommentList, func(s *DailyStats, v int64) { s.Comments = v })
+	merge(t.ShareList, func(s *DailyStats, v int64) { s.Shares = v })
+	merge(t.RiseFansList, func(s *DailyStats, v int64) { s.NewFans = v })
+
+	stats := make([]DailyStats, 0, len(days))
+	for _, s := range days {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Date.Before(stats[j].Date) })
+	return stats
+}
+
+// GetNoteMetrics 获取单篇笔记的观看、点赞、收藏、评论、分享与涨粉数据
+func (c *XhsClient) GetNoteMetrics(ctx context.Context, noteID string) (*NoteMetrics, error) {
+	var result struct {
+		BaseResponse
+		Data NoteMetrics `json:"data"`
+	}
+	err := c.getJSON(ctx, creatorHost+"/api/galaxy/creator/datacenter/note/base", creatorDataReferer, map[string]string{
+		"note_id": noteID,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get note metrics"); err != nil {
+		return nil, err
+	}
+	metrics := result.Data
+	metrics.NoteID = noteID
+	return &metrics, nil
+}
+
+// GetAccountDailyStats 获取账号在 [start, end] 日期范围内按天的数据
+func (c *XhsClient) GetAccountDailyStats(ctx context.Context, start, end time.Time) ([]DailyStats, error) {
+	var result struct {
+		BaseResponse
+		Data accountTrend `json:"data"`
+	}
+	err := c.getJSON(ctx, creatorHost+"/api/galaxy/creator/datacenter/account/trend", creatorDataReferer, map[string]string{
+		"begin_date": start.Format(time.DateOnly),
+		"end_date":   end.Format(time.DateOnly),
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get account daily stats"); err != nil {
+		return nil, err
+	}
+	return result.Data.dailyStats(), nil
+}
@@ -0,0 +1,32 @@
+package xhs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAccountTrendDailyStats(t *testing.T) {
+	var trend accountTrend
+	err := json.Unmarshal([]byte(`{
+		"view_list":[{"date":1700006400000,"count":20},{"date":1699920000000,"count":10}],
+		"like_list":[{"date":1699920000000,"count":3}],
+		"rise_fans_list":[{"date":1700006400000,"count":1}]
+	}`), &trend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := trend.dailyStats()
+	if len(stats) != 2 {
+		t.Fatalf("dailyStats() returned %d days, want 2", len(stats))
+	}
+	if stats[0].Date.UnixMilli() != 1699920000000 || stats[0].Views != 10 || stats[0].Likes != 3 || stats[0].NewFans != 0 {
+		t.Errorf("stats[0] = %+v", stats[0])
+	}
+	if stats[1].Views != 20 || stats[1].Likes != 0 || stats[1].NewFans != 1 {
+		t.Errorf("stats[1] = %+v", stats[1])
+	}
+	if records := stats[1].Records(); len(records) != 6 || records[5].Metric != "new_fans" || records[5].Value != 1 {
+		t.Errorf("Records() = %+v", records)
+	}
+}
@@ -0,0 +1,116 @@
+package xhs
+
+import (
+	"context"
+	"crawler-sdk/internal/crypto/xhs"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// GuestSession 游客会话的 cookie，可保存后通过 NewGuestFromSession 复用
+//
+// 注意：会话中不包含 gid。gid 由 as.xiaohongshu.com 的设备指纹接口下发，
+// 请求需要浏览器环境生成的指纹，本包不生成。缺少 gid 时部分接口 (如搜索) 可能触发风控，
+// 需要时可从浏览器中复制 gid 写入 Cookies 后再调用 NewGuestFromSession
+type GuestSession struct {
+	A1         string `json:"a1"`
+	WebID      string `json:"web_id"`
+	WebSession string `json:"web_session"`
+	// Cookies 激活过程中服务端下发的其余 cookie，如 websectiga、sec_poison_id、xsecappid
+	Cookies map[string]string `json:"cookies"`
+}
+
+// Cookie 游客会话对应的 cookie 字符串
+func (s *GuestSession) Cookie() string {
+	names := make([]string, 0, len(s.Cookies))
+	for name := range s.Cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := []string{"a1=" + s.A1, "webId=" + s.WebID}
+	for _, name := range names {
+		parts = append(parts, name+"="+s.Cookies[name])
+	}
+	if s.WebSession != "" {
+		parts = append(parts, "web_session="+s.WebSession)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// NewGuest 创建一个不登录的游客客户端，可调用搜索、笔记详情、评论等公开接口
+// 每次调用都会生成新的 a1 与 webId，大规模采集时可为每个 worker 创建独立的游客客户端
+// 游客会话不包含 gid，见 GuestSession
+func NewGuest(ctx context.Context) (*XhsClient, error) {
+	session, err := NewGuestSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewGuestFromSession(session), nil
+}
+
+// NewGuestFromSession 使用已有的游客会话创建客户端
+func NewGuestFromSession(session *GuestSession) *XhsClient {
+	return New(session.Cookie())
+}
+
+// NewGuestSession 生成 a1 与 webId 并激活游客会话
+// 先访问网页端首页领取 xsecappid、websectiga 等 cookie，再调用激活接口获取 web_session
+// 不会获取 gid，见 GuestSession
+func NewGuestSession(ctx context.Context) (*GuestSession, error) {
+	a1, webID := (&xhs.CookieFieldEncrypt{}).GetA1AndWebID()
+	session := &GuestSession{
+		A1:      a1,
+		WebID:   webID,
+		Cookies: map[string]string{},
+	}
+
+	c := New(session.Cookie())
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("User-Agent", userAgent).
+		Get(webHost + "/explore")
+	if err != nil {
+		return nil, fmt.Errorf("请求网页端首页失败: %w", err)
+	}
+	session.setCookies(resp.Cookies())
+
+	// 激活请求需携带首页下发的 cookie
+	c = New(session.Cookie())
+	var result struct {
+		BaseResponse
+		Data struct {
+			Session string `json:"session"`
+			UserID  string `json:"user_id"`
+		} `json:"data"`
+	}
+	if err := c.postJSON(ctx, "/api/sns/web/v1/login/activate", map[string]interface{}{}, &result); err != nil {
+		return nil, err
+	}
+	if err := result.check("activate guest"); err != nil {
+		return nil, err
+	}
+	if result.Data.Session == "" {
+		return nil, errors.New("activate guest failed: no session in response")
+	}
+	session.WebSession = result.Data.Session
+	return session, nil
+}
+
+// setCookies 记录服务端下发的 cookie，a1、webId 与 web_session 单独保存
+func (s *GuestSession) setCookies(cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		switch cookie.Name {
+		case "a1", "webId":
+		case "web_session":
+			s.WebSession = cookie.Value
+		default:
+			if cookie.Value != "" {
+				s.Cookies[cookie.Name] = cookie.Value
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package xhs
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGuestSessionCookie(t *testing.T) {
+	tests := []struct {
+		name    string
+		session GuestSession
+		want    string
+	}{
+		{
+			name:    "before activation",
+			session: GuestSession{A1: "a1v", WebID: "wid"},
+			want:    "a1=a1v; webId=wid",
+		},
+		{
+			name: "cookies sorted by name",
+			session: GuestSession{
+				A1:         "a1v",
+				WebID:      "wid",
+				WebSession: "ws",
+				Cookies:    map[string]string{"xsecappid": "xhs-pc-web", "gid": "g", "websectiga": "w"},
+			},
+			want: "a1=a1v; webId=wid; gid=g; websectiga=w; xsecappid=xhs-pc-web; web_session=ws",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.Cookie(); got != tt.want {
+				t.Errorf("Cookie() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuestSessionSetCookies(t *testing.T) {
+	s := &GuestSession{A1: "a1v", WebID: "wid", Cookies: map[string]string{"xsecappid": "old"}}
+	s.setCookies([]*http.Cookie{
+		{Name: "a1", Value: "server-a1"},
+		{Name: "webId", Value: "server-wid"},
+		{Name: "web_session", Value: "ws"},
+		{Name: "websectiga", Value: "w"},
+		{Name: "xsecappid", Value: "xhs-pc-web"},
+		{Name: "acw_tc", Value: ""},
+	})
+
+	if s.A1 != "a1v" || s.WebID != "wid" {
+		t.Errorf("generated a1/webId were overwritten: %q %q", s.A1, s.WebID)
+	}
+	if s.WebSession != "ws" {
+		t.Errorf("WebSession = %q, want ws", s.WebSession)
+	}
+	want := map[string]string{"websectiga": "w", "xsecappid": "xhs-pc-web"}
+	if len(s.Cookies) != len(want) {
+		t.Errorf("Cookies = %v, want %v", s.Cookies, want)
+	}
+	for k, v := range want {
+		if s.Cookies[k] != v {
+			t.Errorf("Cookies[%q] = %q, want %q", k, s.Cookies[k], v)
+		}
+	}
+}
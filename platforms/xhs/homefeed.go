@@ -0,0 +1,116 @@
+package xhs
+
+import (
+	"context"
+	"sync"
+)
+
+// FeedCategory 首页推荐频道
+type FeedCategory string
+
+const (
+	// FeedRecommend 推荐
+	FeedRecommend FeedCategory = "homefeed_recommend"
+	// FeedFashion 穿搭
+	FeedFashion FeedCategory = "homefeed.fashion_v3"
+	// FeedFood 美食
+	FeedFood FeedCategory = "homefeed.food_v3"
+	// FeedCosmetics 彩妆
+	FeedCosmetics FeedCategory = "homefeed.cosmetics_v3"
+	// FeedMovieTV 影视
+	FeedMovieTV FeedCategory = "homefeed.movie_and_tv_v3"
+	// FeedCareer 职场
+	FeedCareer FeedCategory = "homefeed.career_v3"
+	// FeedLove 情感
+	FeedLove FeedCategory = "homefeed.love_v3"
+	// FeedHousehold 家居
+	FeedHousehold FeedCategory = "homefeed.household_product_v3"
+	// FeedGaming 游戏
+	FeedGaming FeedCategory = "homefeed.gaming_v3"
+	// FeedTravel 旅行
+	FeedTravel FeedCategory = "homefeed.travel_v3"
+	// FeedFitness 健身
+	FeedFitness FeedCategory = "homefeed.fitness_v3"
+)
+
+// 首页推荐的刷新方式
+const (
+	// feedRefreshInit 首次进入频道
+	feedRefreshInit = 1
+	// feedRefreshLoadMore 下滑加载更多
+	feedRefreshLoadMore = 3
+)
+
+// feedState 单个频道的翻页状态
+// mu 在整个请求期间持有，同一频道的请求串行执行，避免并发调用读到同一个 cursor 而拿到重复的一页
+type feedState struct {
+	mu          sync.Mutex
+	cursorScore string
+	noteIndex   int
+}
+
+// HomeFeed 获取频道的下一页推荐笔记
+// 每个频道分别记录 cursor_score 与 note_index，首次调用为刷新，之后的调用为加载更多
+// 同一频道的并发调用会排队执行，不同频道之间互不影响
+func (c *XhsClient) HomeFeed(ctx context.Context, category FeedCategory) ([]NoteCard, error) {
+	if category == "" {
+		category = FeedRecommend
+	}
+
+	c.feedMu.Lock()
+	state, ok := c.feeds[category]
+	if !ok {
+		state = &feedState{}
+		c.feeds[category] = state
+	}
+	c.feedMu.Unlock()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	refreshType := feedRefreshLoadMore
+	if state.cursorScore == "" {
+		refreshType = feedRefreshInit
+	}
+
+	var result struct {
+		BaseResponse
+		Data struct {
+			CursorScore string     `json:"cursor_score"`
+			Items       []noteItem `json:"items"`
+		} `json:"data"`
+	}
+	err := c.postJSON(ctx, "/api/sns/web/v1/homefeed", map[string]interface{}{
+		"cursor_score":         state.cursorScore,
+		"num":                  18,
+		"refresh_type":         refreshType,
+		"note_index":           state.noteIndex,
+		"unread_begin_note_id": "",
+		"unread_end_note_id":   "",
+		"unread_note_count":    0,
+		"category":             category,
+		"search_key":           "",
+		"need_num":             8,
+		"image_formats":        []string{"jpg", "webp", "avif"},
+		"need_filter_image":    false,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("home feed"); err != nil {
+		return nil, err
+	}
+
+	state.cursorScore = result.Data.CursorScore
+	state.noteIndex += len(result.Data.Items)
+
+	return c.noteCards(result.Data.Items), nil
+}
+
+// ResetHomeFeed 清除频道的翻页状态，下次调用 HomeFeed 时重新刷新
+// 正在进行的请求不受影响，其结果也不会写回新的状态
+func (c *XhsClient) ResetHomeFeed(category FeedCategory) {
+	c.feedMu.Lock()
+	defer c.feedMu.Unlock()
+	delete(c.feeds, category)
+}
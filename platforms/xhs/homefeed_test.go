@@ -0,0 +1,66 @@
+package xhs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestHomeFeedConcurrentCalls(t *testing.T) {
+	var mu sync.Mutex
+	var cursors []string
+	c := New("a1=test; webId=test")
+	c.client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var req struct {
+			CursorScore string `json:"cursor_score"`
+			NoteIndex   int    `json:"note_index"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		cursors = append(cursors, req.CursorScore)
+		page := len(cursors)
+		mu.Unlock()
+		// 拉长请求耗时，让并发调用有机会读到同一个 cursor
+		time.Sleep(20 * time.Millisecond)
+
+		body := fmt.Sprintf(`{"success":true,"data":{"cursor_score":"c%d","items":[{"id":"n%d","model_type":"note"},{"id":"m%d","model_type":"note"}]}}`, page, page, page)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.HomeFeed(context.Background(), FeedFood); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := []string{"", "c1", "c2"}
+	if strings.Join(cursors, ",") != strings.Join(want, ",") {
+		t.Errorf("requested cursors = %q, want %q", cursors, want)
+	}
+	if state := c.feeds[FeedFood]; state.noteIndex != 6 || state.cursorScore != "c3" {
+		t.Errorf("feed state = %q/%d, want c3/6", state.cursorScore, state.noteIndex)
+	}
+}
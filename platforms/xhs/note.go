@@ -0,0 +1,181 @@
+package xhs
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// xsecSourcePCFeed 网页端首页/列表进入详情时使用的 xsec_source
+const xsecSourcePCFeed = "pc_feed"
+
+// 笔记类型
+const (
+	// NoteTypeNormal 图文笔记
+	NoteTypeNormal = "normal"
+	// NoteTypeVideo 视频笔记
+	NoteTypeVideo = "video"
+)
+
+// NoteUser 笔记作者
+type NoteUser struct {
+	UserID    string `json:"user_id"`
+	Nickname  string `json:"nickname"`
+	Avatar    string `json:"avatar"`
+	XsecToken string `json:"xsec_token"`
+}
+
+// NoteImage 图文笔记中的图片
+type NoteImage struct {
+	URLDefault string `json:"url_default"`
+	URLPre     string `json:"url_pre"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	LivePhoto  bool   `json:"live_photo"`
+}
+
+// NoteTag 笔记话题
+type NoteTag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// NoteVideoStream 视频的一路播放流
+type NoteVideoStream struct {
+	MasterURL  string   `json:"master_url"`
+	BackupURLs []string `json:"backup_urls"`
+	Width      int      `json:"width"`
+	Height     int      `json:"height"`
+	Size       int64    `json:"size"`
+	VideoCodec string   `json:"video_codec"`
+}
+
+// NoteVideo 视频笔记的视频信息
+type NoteVideo struct {
+	Capa struct {
+		// Duration 视频时长，单位秒
+		Duration int `json:"duration"`
+	} `json:"capa"`
+	Media struct {
+		// Stream 按编码 (h264、h265 等) 分组的播放流
+		Stream map[string][]NoteVideoStream `json:"stream"`
+	} `json:"media"`
+}
+
+// NoteInteractInfo 笔记互动数据，数量字段为接口返回的展示文本 (如 "1.2万")
+type NoteInteractInfo struct {
+	Liked          bool   `json:"liked"`
+	LikedCount     string `json:"liked_count"`
+	Collected      bool   `json:"collected"`
+	CollectedCount string `json:"collected_count"`
+	CommentCount   string `json:"comment_count"`
+	ShareCount     string `json:"share_count"`
+}
+
+// NoteDetail 笔记详情
+type NoteDetail struct {
+	NoteID         string           `json:"note_id"`
+	Type           string           `json:"type"`
+	Title          string           `json:"title"`
+	Desc           string           `json:"desc"`
+	User           NoteUser         `json:"user"`
+	ImageList      []NoteImage      `json:"image_list"`
+	Video          *NoteVideo       `json:"video"`
+	TagList        []NoteTag        `json:"tag_list"`
+	InteractInfo   NoteInteractInfo `json:"interact_info"`
+	Time           int64            `json:"time"`
+	LastUpdateTime int64            `json:"last_update_time"`
+	IPLocation     string           `json:"ip_location"`
+	XsecToken      string           `json:"xsec_token"`
+}
+
+// GetNote 获取笔记详情
+// xsecToken 为空时使用之前列表接口返回并缓存的值；feed 接口失败时回退为解析笔记网页
+func (c *XhsClient) GetNote(ctx context.Context, noteID, xsecToken string) (*NoteDetail, error) {
+	if xsecToken == "" {
+		xsecToken = c.xsecToken(noteID)
+	}
+
+	note, err := c.getNoteFromFeed(ctx, noteID, xsecToken)
+	if err != nil {
+		var pageErr error
+		note, pageErr = c.getNoteFromPage(ctx, noteID, xsecToken)
+		if pageErr != nil {
+			return nil, fmt.Errorf("%w; 回退解析笔记页面失败: %v", err, pageErr)
+		}
+	}
+
+	if note.NoteID == "" {
+		note.NoteID = noteID
+	}
+	if note.XsecToken == "" {
+		note.XsecToken = xsecToken
+	}
+	c.setXsecToken(note.NoteID, note.XsecToken)
+	c.setXsecToken(note.User.UserID, note.User.XsecToken)
+	return note, nil
+}
+
+// getNoteFromFeed 通过 /api/sns/web/v1/feed 接口获取笔记详情
+func (c *XhsClient) getNoteFromFeed(ctx context.Context, noteID, xsecToken string) (*NoteDetail, error) {
+	var result struct {
+		BaseResponse
+		Data struct {
+			Items []struct {
+				ID        string     `json:"id"`
+				ModelType string     `json:"model_type"`
+				NoteCard  NoteDetail `json:"note_card"`
+			} `json:"items"`
+		} `json:"data"`
+	}
+	err := c.postJSON(ctx, "/api/sns/web/v1/feed", map[string]interface{}{
+		"source_note_id": noteID,
+		"image_formats":  []string{"jpg", "webp", "avif"},
+		"extra":          map[string]string{"need_body_topic": "1"},
+		"xsec_source":    xsecSourcePCFeed,
+		"xsec_token":     xsecToken,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("get note"); err != nil {
+		return nil, err
+	}
+	for _, item := range result.Data.Items {
+		if item.ID == noteID {
+			note := item.NoteCard
+			note.NoteID = item.ID
+			return &note, nil
+		}
+	}
+	return nil, fmt.Errorf("笔记详情API未返回笔记 %s", noteID)
+}
+
+// getNoteFromPage 从笔记网页的 __INITIAL_STATE__ 中解析笔记详情
+func (c *XhsClient) getNoteFromPage(ctx context.Context, noteID, xsecToken string) (*NoteDetail, error) {
+	query := url.Values{}
+	if xsecToken != "" {
+		query.Set("xsec_token", xsecToken)
+		query.Set("xsec_source", xsecSourcePCFeed)
+	}
+	pageURL := fmt.Sprintf("%s/explore/%s", webHost, noteID)
+	if len(query) > 0 {
+		pageURL += "?" + query.Encode()
+	}
+
+	state, err := c.fetchInitialState(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	raw := lookup(state, "note", "noteDetailMap", noteID, "note")
+	if raw == nil {
+		return nil, fmt.Errorf("页面中未找到笔记 %s", noteID)
+	}
+
+	var note NoteDetail
+	if err := decodeState(raw, &note); err != nil {
+		return nil, fmt.Errorf("解析笔记数据失败: %w", err)
+	}
+	return &note, nil
+}
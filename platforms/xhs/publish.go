@@ -0,0 +1,291 @@
+package xhs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Privacy 笔记可见范围
+type Privacy int
+
+const (
+	// PrivacyPublic 公开
+	PrivacyPublic Privacy = 0
+	// PrivacyPrivate 仅自己可见
+	PrivacyPrivate Privacy = 1
+	// PrivacySpecificUsers 仅 VisibleUserIDs 中的用户可见
+	PrivacySpecificUsers Privacy = 2
+)
+
+// CommentPermission 笔记评论权限
+type CommentPermission int
+
+const (
+	// CommentEveryone 所有人可评论
+	CommentEveryone CommentPermission = 0
+	// CommentDisabled 关闭评论
+	CommentDisabled CommentPermission = 1
+)
+
+// NotePublishOptions 发布笔记的可选项
+type NotePublishOptions struct {
+	// Topics 话题名称，发布前通过话题搜索接口解析，未出现在正文中的话题追加到正文末尾
+	Topics []string
+	// Mentions 需要 @ 的用户昵称或小红书号，发布前通过用户搜索接口解析
+	Mentions []string
+	Privacy  Privacy
+	// VisibleUserIDs Privacy 为 PrivacySpecificUsers 时可见的用户 ID
+	VisibleUserIDs []string
+	// PostTime 定时发布时间，零值表示立即发布
+	PostTime time.Time
+	// CommentPermission 评论权限
+	CommentPermission CommentPermission
+	// Location 地点，可通过 SearchPOI 搜索后调用 POI.PostLoc 获得，为空时不添加
+	Location *PostLoc
+}
+
+// Topic 话题搜索结果
+type Topic struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Link    string `json:"link"`
+	ViewNum int64  `json:"view_num"`
+}
+
+// SearchTopics 搜索可添加到笔记中的话题
+func (c *XhsClient) SearchTopics(ctx context.Context, keyword string) ([]Topic, error) {
+	var result struct {
+		BaseResponse
+		Data struct {
+			TopicInfoDtos []Topic `json:"topic_info_dtos"`
+		} `json:"data"`
+	}
+	err := c.postJSON(ctx, "/web_api/sns/v1/search/topic", map[string]interface{}{
+		"keyword":               keyword,
+		"suggest_topic_request": map[string]string{"title": "", "desc": ""},
+		"page":                  map[string]int{"page_size": 20, "page": 1},
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("search topics"); err != nil {
+		return nil, err
+	}
+	return result.Data.TopicInfoDtos, nil
+}
+
+// MentionUser 可 @ 的用户
+type MentionUser struct {
+	UserID   string `json:"user_id"`
+	Nickname string `json:"user_nickname"`
+	RedID    string `json:"red_id"`
+	Image    string `json:"image"`
+}
+
+// SearchMentionUsers 搜索可在笔记中 @ 的用户
+func (c *XhsClient) SearchMentionUsers(ctx context.Context, keyword string) ([]MentionUser, error) {
+	var result struct {
+		BaseResponse
+		Data struct {
+			UserInfoDtos []struct {
+				UserBaseDto MentionUser `json:"user_base_dto"`
+			} `json:"user_info_dtos"`
+		} `json:"data"`
+	}
+	err := c.postJSON(ctx, "/web_api/sns/v1/search/user_info", map[string]interface{}{
+		"keyword":   keyword,
+		"search_id": strconv.FormatInt(time.Now().UnixMilli(), 10),
+		"page":      map[string]int{"page_size": 20, "page": 1},
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("search mention users"); err != nil {
+		return nil, err
+	}
+	users := make([]MentionUser, 0, len(result.Data.UserInfoDtos))
+	for _, u := range result.Data.UserInfoDtos {
+		users = append(users, u.UserBaseDto)
+	}
+	return users, nil
+}
+
+// POI 地点搜索结果
+type POI struct {
+	PoiID       string `json:"poi_id"`
+	PoiType     int    `json:"poi_type"`
+	Name        string `json:"name"`
+	Subname     string `json:"subname"`
+	FullAddress string `json:"full_address"`
+	CityName    string `json:"city_name"`
+}
+
+// PostLoc 返回发布笔记时使用的地点信息
+func (p *POI) PostLoc() *PostLoc {
+	return &PostLoc{PoiID: p.PoiID, PoiType: p.PoiType, Name: p.Name, Subname: p.Subname}
+}
+
+// SearchPOI 按关键词搜索可添加到笔记中的地点
+func (c *XhsClient) SearchPOI(ctx context.Context, keyword string) ([]POI, error) {
+	var result struct {
+		BaseResponse
+		Data struct {
+			PoiList []POI `json:"poi_list"`
+		} `json:"data"`
+	}
+	err := c.postJSON(ctx, "/web_api/sns/v1/local/poi/creator/search", map[string]interface{}{
+		"keyword": keyword,
+		"page":    1,
+		"size":    20,
+		"source":  "WEB",
+		"type":    3,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("search poi"); err != nil {
+		return nil, err
+	}
+	return result.Data.PoiList, nil
+}
+
+// resolveTopic 将话题名称解析为 hash_tag，优先选择名称完全一致的话题
+func (c *XhsClient) resolveTopic(ctx context.Context, name string) (NoteHashTag, error) {
+	topics, err := c.SearchTopics(ctx, name)
+	if err != nil {
+		return NoteHashTag{}, err
+	}
+	if len(topics) == 0 {
+		return NoteHashTag{}, fmt.Errorf("未找到话题: %s", name)
+	}
+	t := topics[0]
+	for _, topic := range topics {
+		if topic.Name == name {
+			t = topic
+			break
+		}
+	}
+	return NoteHashTag{ID: t.ID, Name: t.Name, Link: t.Link, Type: "topic"}, nil
+}
+
+// resolveMention 将昵称或小红书号解析为 @ 用户，优先选择完全一致的用户
+func (c *XhsClient) resolveMention(ctx context.Context, name string) (NoteAt, error) {
+	users, err := c.SearchMentionUsers(ctx, name)
+	if err != nil {
+		return NoteAt{}, err
+	}
+	if len(users) == 0 {
+		return NoteAt{}, fmt.Errorf("未找到用户: %s", name)
+	}
+	u := users[0]
+	for _, user := range users {
+		if user.Nickname == name || user.RedID == name {
+			u = user
+			break
+		}
+	}
+	return NoteAt{UserID: u.UserID, Nickname: u.Nickname, Name: u.Nickname}, nil
+}
+
+// noteCommon 构建发布笔记请求体中的 common 部分，解析话题与 @ 用户并生成 business_binds
+func (c *XhsClient) noteCommon(ctx context.Context, noteType, title, desc string, opts *NotePublishOptions) (CommonInfo, error) {
+	if opts == nil {
+		opts = &NotePublishOptions{}
+	}
+	if err := opts.validate(time.Now()); err != nil {
+		return CommonInfo{}, err
+	}
+
+	hashTags := []NoteHashTag{}
+	for _, name := range opts.Topics {
+		tag, err := c.resolveTopic(ctx, name)
+		if err != nil {
+			return CommonInfo{}, err
+		}
+		hashTags = append(hashTags, tag)
+	}
+	ats := []NoteAt{}
+	for _, name := range opts.Mentions {
+		at, err := c.resolveMention(ctx, name)
+		if err != nil {
+			return CommonInfo{}, err
+		}
+		ats = append(ats, at)
+	}
+	return buildNoteCommon(noteType, title, desc, hashTags, ats, opts)
+}
+
+// validate 检查可见范围与定时发布时间，now 为当前时间
+func (o *NotePublishOptions) validate(now time.Time) error {
+	if o.Privacy == PrivacySpecificUsers && len(o.VisibleUserIDs) == 0 {
+		return fmt.Errorf("指定用户可见时 VisibleUserIDs 不能为空")
+	}
+	if !o.PostTime.IsZero() && !o.PostTime.After(now) {
+		return fmt.Errorf("定时发布时间 %s 早于当前时间", o.PostTime.Format(time.DateTime))
+	}
+	return nil
+}
+
+// noteDesc 将正文中缺少的话题与 @ 标记追加到正文末尾
+func noteDesc(desc string, hashTags []NoteHashTag, ats []NoteAt) string {
+	for _, tag := range hashTags {
+		if mark := "#" + tag.Name + "[话题]#"; !strings.Contains(desc, mark) {
+			desc += " " + mark
+		}
+	}
+	for _, at := range ats {
+		if mark := "@" + at.Nickname; !strings.Contains(desc, mark) {
+			desc += " " + mark + " "
+		}
+	}
+	return strings.TrimSpace(desc)
+}
+
+// buildNoteCommon 使用已解析的话题与 @ 用户生成 common 部分
+func buildNoteCommon(noteType, title, desc string, hashTags []NoteHashTag, ats []NoteAt, opts *NotePublishOptions) (CommonInfo, error) {
+	binds := BusinessBinds{
+		Version:                   1,
+		CoProduceBind:             CoProduceBind{Enable: true},
+		NoteCopyBind:              NoteCopyBind{Copyable: true},
+		InteractionPermissionBind: InteractionPermissionBind{CommentPermission: int(opts.CommentPermission)},
+		OptionRelationList:        []interface{}{},
+	}
+	if !opts.PostTime.IsZero() {
+		binds.NotePostTiming.PostTime = opts.PostTime.UnixMilli()
+	}
+	bindsJSON, err := json.Marshal(binds)
+	if err != nil {
+		return CommonInfo{}, err
+	}
+
+	userIDs := []string{}
+	if opts.Privacy == PrivacySpecificUsers {
+		userIDs = opts.VisibleUserIDs
+	}
+
+	return CommonInfo{
+		Type:          noteType,
+		NoteID:        "",
+		Source:        "{\"type\":\"web\",\"ids\":\"\",\"extraInfo\":\"{\\\"subType\\\":\\\"official\\\",\\\"systemId\\\":\\\"web\\\"}\"}",
+		Title:         title,
+		Desc:          noteDesc(desc, hashTags, ats),
+		Ats:           ats,
+		HashTag:       hashTags,
+		BusinessBinds: string(bindsJSON),
+		PrivacyInfo: PrivacyInfo{
+			OpType:  1,
+			Type:    int(opts.Privacy),
+			UserIDs: userIDs,
+		},
+		GoodsInfo:    map[string]interface{}{},
+		BizRelations: []string{},
+		CapaTraceInfo: CapaTraceInfo{
+			ContextJson: "{\"longTextToImage\":{\"imageFileIds\":[]},\"recommend_title\":{\"recommend_title_id\":\"\",\"is_use\":3,\"used_index\":-1},\"recommendTitle\":[],\"recommend_topics\":{\"used\":[]}}",
+		},
+		PostLoc: opts.Location,
+	}, nil
+}
@@ -0,0 +1,115 @@
+package xhs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNotePublishOptionsValidate(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		opts    NotePublishOptions
+		wantErr bool
+	}{
+		{"defaults", NotePublishOptions{}, false},
+		{"private", NotePublishOptions{Privacy: PrivacyPrivate}, false},
+		{"specific users without ids", NotePublishOptions{Privacy: PrivacySpecificUsers}, true},
+		{"specific users", NotePublishOptions{Privacy: PrivacySpecificUsers, VisibleUserIDs: []string{"u1"}}, false},
+		{"future post time", NotePublishOptions{PostTime: now.Add(time.Hour)}, false},
+		{"post time now", NotePublishOptions{PostTime: now}, true},
+		{"past post time", NotePublishOptions{PostTime: now.Add(-time.Hour)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.validate(now); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNoteDesc(t *testing.T) {
+	tags := []NoteHashTag{{Name: "咖啡"}, {Name: "探店"}}
+	ats := []NoteAt{{Nickname: "小明"}}
+	tests := []struct {
+		name string
+		desc string
+		want string
+	}{
+		{"appends missing marks", "今天喝了 ☕️", "今天喝了 ☕️ #咖啡[话题]# #探店[话题]# @小明"},
+		{"keeps existing marks", "#咖啡[话题]# 和 @小明 一起", "#咖啡[话题]# 和 @小明 一起 #探店[话题]#"},
+		{"empty desc", "", "#咖啡[话题]# #探店[话题]# @小明"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := noteDesc(tt.desc, tags, ats); got != tt.want {
+				t.Errorf("noteDesc() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := noteDesc(" 正文 ", nil, nil); got != "正文" {
+		t.Errorf("noteDesc() without marks = %q", got)
+	}
+}
+
+func TestBuildNoteCommon(t *testing.T) {
+	postTime := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	loc := &PostLoc{PoiID: "poi1", PoiType: 1, Name: "咖啡馆"}
+	tests := []struct {
+		name        string
+		opts        NotePublishOptions
+		wantBinds   string
+		wantPrivacy PrivacyInfo
+	}{
+		{
+			name:        "defaults",
+			opts:        NotePublishOptions{},
+			wantBinds:   `{"version":1,"noteId":0,"bizType":0,"noteOrderBind":{},"notePostTiming":{},"noteCollectionBind":{"id":""},"noteSketchCollectionBind":{"id":""},"coProduceBind":{"enable":true},"noteCopyBind":{"copyable":true},"interactionPermissionBind":{"commentPermission":0},"optionRelationList":[]}`,
+			wantPrivacy: PrivacyInfo{OpType: 1, Type: 0, UserIDs: []string{}},
+		},
+		{
+			name: "scheduled private with comments disabled",
+			opts: NotePublishOptions{
+				Privacy:           PrivacyPrivate,
+				PostTime:          postTime,
+				CommentPermission: CommentDisabled,
+				VisibleUserIDs:    []string{"ignored"},
+				Location:          loc,
+			},
+			wantBinds:   `{"version":1,"noteId":0,"bizType":0,"noteOrderBind":{},"notePostTiming":{"postTime":1893553445000},"noteCollectionBind":{"id":""},"noteSketchCollectionBind":{"id":""},"coProduceBind":{"enable":true},"noteCopyBind":{"copyable":true},"interactionPermissionBind":{"commentPermission":1},"optionRelationList":[]}`,
+			wantPrivacy: PrivacyInfo{OpType: 1, Type: 1, UserIDs: []string{}},
+		},
+		{
+			name:        "specific users",
+			opts:        NotePublishOptions{Privacy: PrivacySpecificUsers, VisibleUserIDs: []string{"u1", "u2"}},
+			wantPrivacy: PrivacyInfo{OpType: 1, Type: 2, UserIDs: []string{"u1", "u2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			common, err := buildNoteCommon(NoteTypeNormal, "标题", "正文", []NoteHashTag{}, []NoteAt{}, &tt.opts)
+			if err != nil {
+				t.Fatalf("buildNoteCommon() error = %v", err)
+			}
+			if !json.Valid([]byte(common.BusinessBinds)) {
+				t.Fatalf("business_binds is not valid JSON: %s", common.BusinessBinds)
+			}
+			if tt.wantBinds != "" && common.BusinessBinds != tt.wantBinds {
+				t.Errorf("business_binds = %s\nwant %s", common.BusinessBinds, tt.wantBinds)
+			}
+			got, _ := json.Marshal(common.PrivacyInfo)
+			want, _ := json.Marshal(tt.wantPrivacy)
+			if string(got) != string(want) {
+				t.Errorf("privacy_info = %s, want %s", got, want)
+			}
+			if common.PostLoc != tt.opts.Location {
+				t.Errorf("post_loc = %+v, want %+v", common.PostLoc, tt.opts.Location)
+			}
+			if common.Type != NoteTypeNormal || common.Title != "标题" || common.Desc != "正文" {
+				t.Errorf("common = %+v", common)
+			}
+		})
+	}
+}
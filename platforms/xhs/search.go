@@ -0,0 +1,312 @@
+package xhs
+
+import (
+	"context"
+	"crawler-sdk/internal/crypto/xhs"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// SearchSort 笔记搜索排序方式
+type SearchSort string
+
+const (
+	// SortGeneral 综合
+	SortGeneral SearchSort = "general"
+	// SortLatest 最新
+	SortLatest SearchSort = "time_descending"
+	// SortMostLiked 最多点赞
+	SortMostLiked SearchSort = "popularity_descending"
+)
+
+// SearchNoteType 笔记搜索的类型筛选
+type SearchNoteType int
+
+const (
+	// SearchNoteAll 全部
+	SearchNoteAll SearchNoteType = 0
+	// SearchNoteVideo 视频
+	SearchNoteVideo SearchNoteType = 1
+	// SearchNoteImage 图文
+	SearchNoteImage SearchNoteType = 2
+)
+
+// SearchOptions 笔记搜索选项
+type SearchOptions struct {
+	Sort     SearchSort
+	NoteType SearchNoteType
+	// PageSize 每页数量，默认 20
+	PageSize int
+}
+
+// NoteCard 搜索、首页推荐等列表中的笔记卡片
+type NoteCard struct {
+	NoteID       string           `json:"note_id"`
+	XsecToken    string           `json:"xsec_token"`
+	Type         string           `json:"type"`
+	DisplayTitle string           `json:"display_title"`
+	User         NoteUser         `json:"user"`
+	InteractInfo NoteInteractInfo `json:"interact_info"`
+	Cover        NoteImage        `json:"cover"`
+}
+
+// noteItem 列表接口返回的条目，model_type 为 note 时 note_card 有效
+type noteItem struct {
+	ID        string   `json:"id"`
+	ModelType string   `json:"model_type"`
+	XsecToken string   `json:"xsec_token"`
+	NoteCard  NoteCard `json:"note_card"`
+}
+
+// noteCards 过滤出笔记条目并缓存其中的 xsec_token
+func (c *XhsClient) noteCards(items []noteItem) []NoteCard {
+	cards := make([]NoteCard, 0, len(items))
+	for _, item := range items {
+		if item.ModelType != "note" {
+			continue
+		}
+		card := item.NoteCard
+		card.NoteID = item.ID
+		card.XsecToken = item.XsecToken
+		c.setXsecToken(card.NoteID, card.XsecToken)
+		c.setXsecToken(card.User.UserID, card.User.XsecToken)
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// NoteSearchIterator 遍历笔记搜索结果，同一次搜索的各页共用 search_id
+//
+//	it, err := client.SearchNotes(ctx, "咖啡", &xhs.SearchOptions{Sort: xhs.SortLatest})
+//	if err != nil {
+//		...
+//	}
+//	for it.HasMore() {
+//		notes, err := it.Next(ctx)
+//		...
+//	}
+type NoteSearchIterator struct {
+	c        *XhsClient
+	keyword  string
+	opts     SearchOptions
+	searchID string
+
+	page    int
+	hasMore bool
+
+	// first 构造迭代器时已获取的第一页，由第一次 Next 返回
+	first      []NoteCard
+	firstReady bool
+}
+
+// SearchNotes 按关键词搜索笔记，获取第一页并返回迭代器
+// opts 为 nil 时使用综合排序、不筛选类型
+func (c *XhsClient) SearchNotes(ctx context.Context, keyword string, opts *SearchOptions) (*NoteSearchIterator, error) {
+	o := SearchOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Sort == "" {
+		o.Sort = SortGeneral
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = 20
+	}
+	it := &NoteSearchIterator{
+		c:        c,
+		keyword:  keyword,
+		opts:     o,
+		searchID: xhs.NewMiscEncrypt().SearchID(),
+		page:     1,
+		hasMore:  true,
+	}
+	notes, err := it.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	it.first, it.firstReady = notes, true
+	return it, nil
+}
+
+// HasMore 是否还有下一页
+func (it *NoteSearchIterator) HasMore() bool {
+	return it.firstReady || it.hasMore
+}
+
+// Next 获取下一页搜索结果
+func (it *NoteSearchIterator) Next(ctx context.Context) ([]NoteCard, error) {
+	if it.firstReady {
+		notes := it.first
+		it.first, it.firstReady = nil, false
+		return notes, nil
+	}
+	if !it.hasMore {
+		return nil, nil
+	}
+	return it.fetch(ctx)
+}
+
+// fetch 请求当前页的搜索结果并翻页
+func (it *NoteSearchIterator) fetch(ctx context.Context) ([]NoteCard, error) {
+	var result struct {
+		BaseResponse
+		Data struct {
+			Items   []noteItem `json:"items"`
+			HasMore bool       `json:"has_more"`
+		} `json:"data"`
+	}
+	err := it.c.postJSON(ctx, "/api/sns/web/v1/search/notes", map[string]interface{}{
+		"keyword":       it.keyword,
+		"page":          it.page,
+		"page_size":     it.opts.PageSize,
+		"search_id":     it.searchID,
+		"sort":          it.opts.Sort,
+		"note_type":     it.opts.NoteType,
+		"ext_flags":     []string{},
+		"image_formats": []string{"jpg", "webp", "avif"},
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("search notes"); err != nil {
+		return nil, err
+	}
+
+	it.page++
+	it.hasMore = result.Data.HasMore
+	return it.c.noteCards(result.Data.Items), nil
+}
+
+// SearchUser 用户搜索结果
+type SearchUser struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Image     string `json:"image"`
+	RedID     string `json:"red_id"`
+	SubTitle  string `json:"sub_title"`
+	Fans      string `json:"fans"`
+	NoteCount int    `json:"note_count"`
+	Followed  bool   `json:"followed"`
+	XsecToken string `json:"xsec_token"`
+}
+
+// UserSearchIterator 遍历用户搜索结果
+type UserSearchIterator struct {
+	c        *XhsClient
+	keyword  string
+	searchID string
+
+	page    int
+	hasMore bool
+
+	// first 构造迭代器时已获取的第一页，由第一次 Next 返回
+	first      []SearchUser
+	firstReady bool
+}
+
+// SearchUsers 按关键词搜索用户，获取第一页并返回迭代器
+func (c *XhsClient) SearchUsers(ctx context.Context, keyword string) (*UserSearchIterator, error) {
+	it := &UserSearchIterator{
+		c:        c,
+		keyword:  keyword,
+		searchID: xhs.NewMiscEncrypt().SearchID(),
+		page:     1,
+		hasMore:  true,
+	}
+	users, err := it.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	it.first, it.firstReady = users, true
+	return it, nil
+}
+
+// HasMore 是否还有下一页
+func (it *UserSearchIterator) HasMore() bool {
+	return it.firstReady || it.hasMore
+}
+
+// Next 获取下一页搜索结果
+func (it *UserSearchIterator) Next(ctx context.Context) ([]SearchUser, error) {
+	if it.firstReady {
+		users := it.first
+		it.first, it.firstReady = nil, false
+		return users, nil
+	}
+	if !it.hasMore {
+		return nil, nil
+	}
+	return it.fetch(ctx)
+}
+
+// fetch 请求当前页的搜索结果并翻页
+func (it *UserSearchIterator) fetch(ctx context.Context) ([]SearchUser, error) {
+	var result struct {
+		BaseResponse
+		Data struct {
+			Users   []SearchUser `json:"users"`
+			HasMore bool         `json:"has_more"`
+		} `json:"data"`
+	}
+	err := it.c.postJSON(ctx, "/api/sns/web/v1/search/usersearch", map[string]interface{}{
+		"search_user_request": map[string]interface{}{
+			"keyword":    it.keyword,
+			"search_id":  it.searchID,
+			"page":       it.page,
+			"page_size":  15,
+			"biz_type":   "web_search_user",
+			"request_id": strconv.Itoa(rand.Intn(1e9)) + "-" + strconv.FormatInt(time.Now().UnixMilli(), 10),
+		},
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.check("search users"); err != nil {
+		return nil, err
+	}
+
+	for _, u := range result.Data.Users {
+		it.c.setXsecToken(u.ID, u.XsecToken)
+	}
+	it.page++
+	it.hasMore = result.Data.HasMore
+	return result.Data.Users, nil
+}
+
+// SearchSuggestions 获取关键词联想词
+func (c *XhsClient) SearchSuggestions(ctx context.Context, keyword string) ([]string, error) {
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParam("keyword", keyword).
+		Get(apiHost + "/api/sns/web/v1/search/recommend")
+	if err != nil {
+		return nil, fmt.Errorf("请求搜索联想API失败: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("请求搜索联想API返回错误状态: %s, body: %s", resp.Status(), resp.String())
+	}
+
+	var result struct {
+		BaseResponse
+		Data struct {
+			SugItems []struct {
+				Text string `json:"text"`
+			} `json:"sug_items"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(resp.String()), &result); err != nil {
+		return nil, fmt.Errorf("解析搜索联想API响应失败: %w", err)
+	}
+	if err := result.check("search suggestions"); err != nil {
+		return nil, err
+	}
+
+	words := make([]string, 0, len(result.Data.SugItems))
+	for _, item := range result.Data.SugItems {
+		words = append(words, item.Text)
+	}
+	return words, nil
+}
@@ -0,0 +1,60 @@
+package xhs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestSearchNotesPaging(t *testing.T) {
+	var pages []map[string]interface{}
+	c := New("a1=test; webId=test")
+	c.client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, err
+		}
+		pages = append(pages, req)
+		if req["page"].(float64) == 1 {
+			return jsonResponse(r, `{"success":true,"data":{"has_more":true,"items":[{"id":"n1","model_type":"note","xsec_token":"t1"},{"id":"q1","model_type":"rec_query"}]}}`), nil
+		}
+		return jsonResponse(r, `{"success":true,"data":{"has_more":false,"items":[{"id":"n2","model_type":"note"}]}}`), nil
+	}))
+	ctx := context.Background()
+
+	it, err := c.SearchNotes(ctx, "咖啡", &SearchOptions{Sort: SortLatest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 1 {
+		t.Fatalf("SearchNotes() sent %d requests, want the first page only", len(pages))
+	}
+	var notes []NoteCard
+	for it.HasMore() {
+		page, err := it.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		notes = append(notes, page...)
+	}
+	if len(notes) != 2 || notes[0].NoteID != "n1" || notes[1].NoteID != "n2" {
+		t.Errorf("notes = %+v", notes)
+	}
+	if pages[0]["search_id"] != pages[1]["search_id"] || pages[0]["sort"] != string(SortLatest) || pages[0]["page_size"].(float64) != 20 {
+		t.Errorf("requests = %v", pages)
+	}
+	if got := c.xsecToken("n1"); got != "t1" {
+		t.Errorf("note xsec_token = %q, want t1", got)
+	}
+}
+
+func TestSearchUsersError(t *testing.T) {
+	c := New("a1=test; webId=test")
+	c.client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return jsonResponse(r, `{"success":false,"code":300011,"msg":"账号异常"}`), nil
+	}))
+	if _, err := c.SearchUsers(context.Background(), "咖啡"); err == nil {
+		t.Fatal("SearchUsers() expected error")
+	}
+}
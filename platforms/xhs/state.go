@@ -0,0 +1,233 @@
+package xhs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var initialStateRe = regexp.MustCompile(`window\.__INITIAL_STATE__=`)
+
+// fetchInitialState 请求网页并解析其中的 window.__INITIAL_STATE__
+func (c *XhsClient) fetchInitialState(ctx context.Context, pageURL string) (map[string]interface{}, error) {
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("User-Agent", userAgent).
+		Get(pageURL)
+
+	if err != nil {
+		return nil, fmt.Errorf("请求页面失败: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("请求页面返回错误状态: %s", resp.Status())
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(resp.String()))
+	if err != nil {
+		return nil, fmt.Errorf("解析HTML失败: %w", err)
+	}
+
+	scriptContent := doc.Find("script").FilterFunction(func(i int, s *goquery.Selection) bool {
+		return initialStateRe.MatchString(s.Text())
+	}).First().Text()
+
+	if scriptContent == "" {
+		return nil, fmt.Errorf("在HTML中未找到 __INITIAL_STATE__")
+	}
+
+	literal := scriptContent[initialStateRe.FindStringIndex(scriptContent)[1]:]
+	jsonStr, err := jsToJSON(literal)
+	if err != nil {
+		return nil, fmt.Errorf("转换 __INITIAL_STATE__ 失败: %w", err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
+		return nil, fmt.Errorf("解析JSON失败: %w", err)
+	}
+	return data, nil
+}
+
+// jsToJSON 将 JS 对象字面量转换为 JSON
+// 处理 undefined/NaN/Infinity、未加引号的键、单引号字符串与 \x 转义，
+// 字符串内容原样保留；在最外层对象闭合处结束，忽略其后的分号等内容
+func jsToJSON(s string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(s))
+	depth := 0
+	i := 0
+	for i < len(s) {
+		ch := s[i]
+		switch {
+		case ch == '"' || ch == '\'':
+			n, err := writeJSString(&b, s[i:])
+			if err != nil {
+				return "", err
+			}
+			i += n
+			continue
+		case ch == '{' || ch == '[':
+			depth++
+		case ch == '}' || ch == ']':
+			depth--
+			if depth < 0 {
+				return "", fmt.Errorf("unexpected %q at offset %d", ch, i)
+			}
+			if depth == 0 {
+				b.WriteByte(ch)
+				return b.String(), nil
+			}
+		case ch >= '0' && ch <= '9':
+			// 数字原样输出，避免把指数部分当作标识符
+			j := i + 1
+			for j < len(s) && strings.IndexByte("0123456789.eE+-", s[j]) >= 0 {
+				j++
+			}
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		case ch == '-' && strings.HasPrefix(s[i+1:], "Infinity"):
+			b.WriteString("null")
+			i += 1 + len("Infinity")
+			continue
+		case isIdentStart(ch):
+			j := i + 1
+			for j < len(s) && isIdentPart(s[j]) {
+				j++
+			}
+			word := s[i:j]
+			switch word {
+			case "undefined", "NaN", "Infinity":
+				b.WriteString("null")
+			case "true", "false", "null":
+				b.WriteString(word)
+			default:
+				// 未加引号的对象键
+				b.WriteString(strconv.Quote(word))
+			}
+			i = j
+			continue
+		}
+		b.WriteByte(ch)
+		i++
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("unterminated object literal")
+	}
+	return b.String(), nil
+}
+
+// writeJSString 将 s 开头的 JS 字符串写为 JSON 字符串，返回消耗的字节数
+func writeJSString(b *strings.Builder, s string) (int, error) {
+	quote := s[0]
+	b.WriteByte('"')
+	for i := 1; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == quote:
+			b.WriteByte('"')
+			return i + 1, nil
+		case ch == '"':
+			// 单引号字符串中的双引号需要转义
+			b.WriteString(`\"`)
+		case ch == '\\':
+			if i+1 >= len(s) {
+				return 0, fmt.Errorf("unterminated string")
+			}
+			i++
+			switch next := s[i]; next {
+			case '\'':
+				b.WriteByte('\'')
+			case 'x':
+				if i+2 >= len(s) {
+					return 0, fmt.Errorf("invalid \\x escape")
+				}
+				b.WriteString(`\u00`)
+				b.WriteString(s[i+1 : i+3])
+				i += 2
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't', 'u':
+				b.WriteByte('\\')
+				b.WriteByte(next)
+			case '\n':
+				// 行续接
+			default:
+				// JS 中其他字符的转义即字符本身
+				b.WriteByte(next)
+			}
+		case ch < 0x20:
+			fmt.Fprintf(b, `\u%04x`, ch)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return 0, fmt.Errorf("unterminated string")
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || ch == '$' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+func isIdentPart(ch byte) bool {
+	return isIdentStart(ch) || ch >= '0' && ch <= '9'
+}
+
+// lookup 按 keys 逐层读取嵌套的 map，任一层不存在时返回 nil
+func lookup(v interface{}, keys ...string) interface{} {
+	for _, k := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+// decodeState 将页面状态中驼峰命名的对象解码到使用 API 下划线字段名的结构体
+func decodeState(v interface{}, out interface{}) error {
+	data, err := json.Marshal(snakeKeys(v))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// snakeKeys 递归地将 map 的键由 camelCase 转为 snake_case
+func snakeKeys(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[toSnake(k)] = snakeKeys(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, item := range val {
+			s[i] = snakeKeys(item)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+func toSnake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package xhs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSToJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "undefined values",
+			in:   `{"a":undefined,"b":[1,undefined],"c":NaN,"d":-Infinity}`,
+			want: `{"a":null,"b":[1,null],"c":null,"d":null}`,
+		},
+		{
+			name: "undefined inside strings is kept",
+			in:   `{"desc":"this is undefined behaviour","x":undefined}`,
+			want: `{"desc":"this is undefined behaviour","x":null}`,
+		},
+		{
+			name: "unquoted keys and single quotes",
+			in:   `{note:{title:'it\'s "ok"',n:1e3}}`,
+			want: `{"note":{"title":"it's \"ok\"","n":1e3}}`,
+		},
+		{
+			name: "hex escape and trailing statement",
+			in:   `{"s":"a\x2Fb/"};window.__SETUP_SERVER_STATE__={}`,
+			want: `{"s":"a\u002Fb/"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsToJSON(tt.in)
+			if err != nil {
+				t.Fatalf("jsToJSON() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("jsToJSON() = %s, want %s", got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("jsToJSON() produced invalid JSON: %s", got)
+			}
+		})
+	}
+}
+
+func TestJSToJSONUnterminated(t *testing.T) {
+	for _, in := range []string{`{"a":1`, `{"a":"b}`} {
+		if _, err := jsToJSON(in); err == nil {
+			t.Errorf("jsToJSON(%q) expected error", in)
+		}
+	}
+}
+
+func TestDecodeState(t *testing.T) {
+	in, err := jsToJSON(`{"noteId":"abc","interactInfo":{"likedCount":"1.2万"},"imageList":[{"urlDefault":"u","livePhoto":false}],"lastUpdateTime":1700000000000,"ipLocation":undefined}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw interface{}
+	if err := json.Unmarshal([]byte(in), &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	var note NoteDetail
+	if err := decodeState(raw, &note); err != nil {
+		t.Fatalf("decodeState() error = %v", err)
+	}
+	if note.NoteID != "abc" || note.InteractInfo.LikedCount != "1.2万" || note.LastUpdateTime != 1700000000000 {
+		t.Errorf("decodeState() = %+v", note)
+	}
+	if len(note.ImageList) != 1 || note.ImageList[0].URLDefault != "u" {
+		t.Errorf("decodeState() image list = %+v", note.ImageList)
+	}
+}
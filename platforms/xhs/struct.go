@@ -3,10 +3,11 @@ package xhs
 // 3. 发布笔记 (函数和结构体已根据 69_Full.txt 的日志更新)
 
 // PublishNotePayload 是根据抓包结果创建的发布笔记的完整请求体结构
+// 图文笔记 image_info 有值、video_info 为 null，视频笔记相反
 type PublishNotePayload struct {
-	Common    CommonInfo  `json:"common"`
-	ImageInfo interface{} `json:"image_info"` // 根据日志为 null，具体结构未知
-	VideoInfo VideoInfo   `json:"video_info"`
+	Common    CommonInfo `json:"common"`
+	ImageInfo *ImageInfo `json:"image_info"`
+	VideoInfo *VideoInfo `json:"video_info"`
 }
 
 type CommonInfo struct {
@@ -15,13 +16,69 @@ type CommonInfo struct {
 	Source        string        `json:"source"`
 	Title         string        `json:"title"`
 	Desc          string        `json:"desc"`
-	Ats           []string      `json:"ats"`
-	HashTag       []string      `json:"hash_tag"`
-	BusinessBinds string        `json:"business_binds"`
+	Ats           []NoteAt      `json:"ats"`
+	HashTag       []NoteHashTag `json:"hash_tag"`
+	BusinessBinds string        `json:"business_binds"` // BusinessBinds 序列化后的 JSON 字符串
 	PrivacyInfo   PrivacyInfo   `json:"privacy_info"`
 	GoodsInfo     interface{}   `json:"goods_info"` // 根据日志为 {}
 	BizRelations  []string      `json:"biz_relations"`
 	CapaTraceInfo CapaTraceInfo `json:"capa_trace_info"`
+	PostLoc       *PostLoc      `json:"post_loc,omitempty"`
+}
+
+type NoteAt struct {
+	UserID   string `json:"user_id"`
+	Nickname string `json:"nickname"`
+	Name     string `json:"name"`
+}
+
+type NoteHashTag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Link string `json:"link"`
+	Type string `json:"type"`
+}
+
+type PostLoc struct {
+	PoiID   string `json:"poi_id"`
+	PoiType int    `json:"poi_type"`
+	Name    string `json:"name"`
+	Subname string `json:"subname"`
+}
+
+type BusinessBinds struct {
+	Version                   int                       `json:"version"`
+	NoteID                    int                       `json:"noteId"`
+	BizType                   int                       `json:"bizType"`
+	NoteOrderBind             struct{}                  `json:"noteOrderBind"`
+	NotePostTiming            NotePostTiming            `json:"notePostTiming"`
+	NoteCollectionBind        IDBind                    `json:"noteCollectionBind"`
+	NoteSketchCollectionBind  IDBind                    `json:"noteSketchCollectionBind"`
+	CoProduceBind             CoProduceBind             `json:"coProduceBind"`
+	NoteCopyBind              NoteCopyBind              `json:"noteCopyBind"`
+	InteractionPermissionBind InteractionPermissionBind `json:"interactionPermissionBind"`
+	OptionRelationList        []interface{}             `json:"optionRelationList"`
+}
+
+type NotePostTiming struct {
+	// PostTime 定时发布时间的毫秒时间戳，立即发布时省略
+	PostTime int64 `json:"postTime,omitempty"`
+}
+
+type IDBind struct {
+	ID string `json:"id"`
+}
+
+type CoProduceBind struct {
+	Enable bool `json:"enable"`
+}
+
+type NoteCopyBind struct {
+	Copyable bool `json:"copyable"`
+}
+
+type InteractionPermissionBind struct {
+	CommentPermission int `json:"commentPermission"`
 }
 
 type PrivacyInfo struct {
@@ -34,6 +91,28 @@ type CapaTraceInfo struct {
 	ContextJson string `json:"contextJson"`
 }
 
+type ImageInfo struct {
+	Images []NoteImageInfo `json:"images"`
+}
+
+type NoteImageInfo struct {
+	FileID        string           `json:"file_id"`
+	Width         int              `json:"width"`
+	Height        int              `json:"height"`
+	Metadata      ImageMetadata    `json:"metadata"`
+	Stickers      ImageStickerInfo `json:"stickers"`
+	ExtraInfoJson string           `json:"extra_info_json"`
+}
+
+type ImageMetadata struct {
+	Source int `json:"source"`
+}
+
+type ImageStickerInfo struct {
+	Version  int      `json:"version"`
+	Floating []string `json:"floating"`
+}
+
 type VideoInfo struct {
 	Fileid            string            `json:"fileid"`
 	FileID            string            `json:"file_id"`
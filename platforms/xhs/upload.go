@@ -1,209 +1,380 @@
 package xhs
 
 import (
+	"bytes"
+	"context"
+	"crawler-sdk/internal/media"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	creatorHost = "https://creator.xiaohongshu.com"
+
+	// maxNoteImages 单篇图文笔记最多可上传的图片数
+	maxNoteImages = 18
+)
 
-	"resty.dev/v3"
+// 上传许可的场景
+const (
+	uploadSceneImage = "image"
+	uploadSceneVideo = "video"
 )
 
-// 1. 获取上传许可
-func (c *XhsClient) GetUploadPermit(client *resty.Client) (*resty.Response, error) {
-	fmt.Println("Step 1: Getting upload permit...")
+// UploadPermit 上传许可，FileIDs 中的每个 ID 对应一个待上传的文件
+type UploadPermit struct {
+	FileIDs    []string `json:"fileIds"`
+	Token      string   `json:"token"`
+	UploadAddr string   `json:"uploadAddr"`
+	ExpireTime int64    `json:"expireTime"`
+}
+
+// GetUploadPermit 获取 count 个文件的上传许可
+// scene: image 或 video
+func (c *XhsClient) GetUploadPermit(ctx context.Context, scene string, count int) (*UploadPermit, error) {
 	resp, err := c.client.R().
+		SetContext(ctx).
 		SetQueryParams(map[string]string{
 			"biz_name":   "spectrum",
-			"scene":      "image", // 如果是视频，这里可能是 "video"
-			"file_count": "1",
+			"scene":      scene,
+			"file_count": fmt.Sprintf("%d", count),
 			"version":    "1",
 			"source":     "web",
 		}).
-		Get("https://creator.xiaohongshu.com/api/media/v1/upload/creator/permit")
-
+		Get(creatorHost + "/api/media/v1/upload/creator/permit")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get upload permit: %w", err)
+		return nil, fmt.Errorf("请求上传许可失败: %w", err)
 	}
-
 	if resp.IsError() {
-		return nil, fmt.Errorf("get upload permit request failed with status: %s", resp.Status())
+		return nil, fmt.Errorf("请求上传许可返回错误状态: %s, body: %s", resp.Status(), resp.String())
 	}
 
-	fmt.Println("Successfully got upload permit.")
-	// 在实际应用中，你需要解析 resp.Body() 来获取 uploadAddr, fileId 和 token
-	// fmt.Println("Response Body:", resp.String())
-	return resp, nil
+	var result struct {
+		BaseResponse
+		Data struct {
+			UploadTempPermits []UploadPermit `json:"uploadTempPermits"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(resp.String()), &result); err != nil {
+		return nil, fmt.Errorf("解析上传许可响应失败: %w", err)
+	}
+	if err := result.check("get upload permit"); err != nil {
+		return nil, err
+	}
+	if len(result.Data.UploadTempPermits) == 0 {
+		return nil, fmt.Errorf("上传许可响应中没有 uploadTempPermits")
+	}
+	permit := &result.Data.UploadTempPermits[0]
+	if len(permit.FileIDs) < count || permit.UploadAddr == "" || permit.Token == "" {
+		return nil, fmt.Errorf("上传许可不完整: %d 个 fileId, uploadAddr: %q", len(permit.FileIDs), permit.UploadAddr)
+	}
+	return permit, nil
 }
 
-// 2. 上传文件
-func (c *XhsClient) UploadFile(permitResp *resty.Response, filePath string) (*resty.Response, error) {
-	fmt.Println("\nStep 2: Uploading file...")
+// uploadFile 将 body 中 size 字节的内容上传到许可中的 fileID，Authorization 由 QSign 中间件计算
+func (c *XhsClient) uploadFile(ctx context.Context, permit *UploadPermit, fileID string, body io.Reader, size int64, contentType string) error {
+	resp, err := c.UploadClient.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetHeaders(map[string]string{
+			"Content-Length":       strconv.FormatInt(size, 10),
+			"Content-Type":         contentType,
+			"Origin":               creatorHost,
+			"Referer":              creatorHost + "/",
+			"User-Agent":           userAgent,
+			"x-cos-security-token": permit.Token,
+		}).
+		Put(fmt.Sprintf("https://%s/%s", permit.UploadAddr, fileID))
+	if err != nil {
+		return fmt.Errorf("上传文件失败: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("上传文件返回错误状态: %s, body: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
 
-	uploadURL := "https://ros-upload-d4.xhscdn.com/spectrum/wc7dm3ayWRWG0399QncgK1AAjBMdETEUhZReftWx49SAtrQ" // 从响应中解析
-	uploadToken := "GODLoWDc60IQpItfzv8AOTnIBNM:eyJkZWFkbGluZSI6..."                                         // 从响应中解析
+// uploadImages 上传图片并返回发布笔记所需的 image_info
+func (c *XhsClient) uploadImages(ctx context.Context, files []string) ([]NoteImageInfo, error) {
+	type imageFile struct {
+		data        []byte
+		contentType string
+		width       int
+		height      int
+	}
+	images := make([]imageFile, 0, len(files))
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		contentType := http.DetectContentType(data)
+		width, height, err := imageSize(data, contentType)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		images = append(images, imageFile{data: data, contentType: contentType, width: width, height: height})
+	}
 
-	fileContent, err := os.ReadFile(filePath)
+	permit, err := c.GetUploadPermit(ctx, uploadSceneImage, len(files))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+		return nil, err
 	}
 
-	resp, err := c.UploadClient.R().
-		SetBody(fileContent).
-		SetHeaders(map[string]string{
-			// 这个 Authorization 头是 PUT 请求特有的，格式和 API 请求的不同
-			"Authorization": "q-sign-algorithm=sha1&q-ak=null&q-sign-time=...", // 从响应中解析或重新生成
-			"Content-Type":  "image/png",                                       // 根据你的文件类型设置
-			"Origin":        "https://creator.xiaohongshu.com",
-			"Referer":       "https://creator.xiaohongshu.com/",
-			"User-Agent":    "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36 Edg/138.0.0.0",
-			// 这个 token 也是从上一步响应中获取的
-			"x-cos-security-token": uploadToken,
-		}).
-		Put(uploadURL)
+	infos := make([]NoteImageInfo, 0, len(images))
+	for i, img := range images {
+		fileID := permit.FileIDs[i]
+		if err := c.uploadFile(ctx, permit, fileID, bytes.NewReader(img.data), int64(len(img.data)), img.contentType); err != nil {
+			return nil, fmt.Errorf("%s: %w", files[i], err)
+		}
+		extra, _ := json.Marshal(map[string]string{"mimeType": img.contentType})
+		infos = append(infos, NoteImageInfo{
+			FileID:        fileID,
+			Width:         img.width,
+			Height:        img.height,
+			Metadata:      ImageMetadata{Source: -1},
+			Stickers:      ImageStickerInfo{Version: 2, Floating: []string{}},
+			ExtraInfoJson: string(extra),
+		})
+	}
+	return infos, nil
+}
 
+// PublishImageNote 上传图片并发布图文笔记，返回笔记 ID
+// files: 图片路径，支持 jpeg、png、gif 与 webp，最多 18 张
+func (c *XhsClient) PublishImageNote(ctx context.Context, files []string, title, desc string, opts *NotePublishOptions) (string, error) {
+	if len(files) == 0 || len(files) > maxNoteImages {
+		return "", fmt.Errorf("图片数量需在 1 到 %d 之间，当前为 %d", maxNoteImages, len(files))
+	}
+
+	common, err := c.noteCommon(ctx, NoteTypeNormal, title, desc, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload file: %w", err)
+		return "", err
 	}
-	if resp.IsError() {
-		return nil, fmt.Errorf("upload file request failed with status: %s", resp.Status())
+	images, err := c.uploadImages(ctx, files)
+	if err != nil {
+		return "", err
 	}
 
-	fmt.Println("Successfully uploaded file.")
-	// 上传成功后，响应头里通常会包含文件的最终 URL 或 ID (如 Etag, X-Ros-Preview-Url)
-	// fmt.Println("Upload Response Headers:", resp.Header())
-	return resp, nil
+	return c.publishNote(ctx, PublishNotePayload{
+		Common:    common,
+		ImageInfo: &ImageInfo{Images: images},
+	})
 }
 
-// publishNote 函数根据提供的参数构建并发送发布笔记的请求。
-// title: 笔记标题
-// desc: 笔记描述
-// videoFileID: 视频文件的 fileId (来自上传步骤)
-// coverFileID: 封面图片的 fileId (来自上传步骤)
-func (c *XhsClient) PublishNote(title, desc, videoFileID, coverFileID string) (*resty.Response, error) {
-	fmt.Println("\nStep 3: Publishing note...")
-
-	publishURL := "https://edith.xiaohongshu.com/web_api/sns/v2/note"
-
-	// 根据 69_Full.txt 的日志文件内容构建请求体
-	// 注意：许多字段（如 metadata）是硬编码的示例值，实际应用中你可能需要动态填充
-	payload := PublishNotePayload{
-		Common: CommonInfo{
-			Type:          "video",
-			NoteID:        "",
-			Source:        "{\"type\":\"web\",\"ids\":\"\",\"extraInfo\":\"{\\\"subType\\\":\\\"official\\\",\\\"systemId\\\":\\\"web\\\"}\"}",
-			Title:         title,
-			Desc:          desc,
-			Ats:           []string{},
-			HashTag:       []string{},
-			BusinessBinds: "{\"version\":1,\"noteId\":0,\"bizType\":0,\"noteOrderBind\":{},\"notePostTiming\":{},\"noteCollectionBind\":{\"id\":\"\"},\"noteSketchCollectionBind\":{\"id\":\"\"},\"coProduceBind\":{\"enable\":true},\"noteCopyBind\":{\"copyable\":true},\"interactionPermissionBind\":{\"commentPermission\":0},\"optionRelationList\":[]}",
-			PrivacyInfo: PrivacyInfo{
-				OpType:  1,
-				Type:    0,
-				UserIDs: []string{},
-			},
-			GoodsInfo:    map[string]interface{}{},
-			BizRelations: []string{},
-			CapaTraceInfo: CapaTraceInfo{
-				ContextJson: "{\"longTextToImage\":{\"imageFileIds\":[]},\"recommend_title\":{\"recommend_title_id\":\"\",\"is_use\":3,\"used_index\":-1},\"recommendTitle\":[],\"recommend_topics\":{\"used\":[]}}",
-			},
-		},
-		ImageInfo: nil,
-		VideoInfo: VideoInfo{
-			Fileid:           videoFileID,
-			FileID:           videoFileID,
-			FormatWidth:      480,
-			FormatHeight:     854,
-			VideoPreviewType: "full_vertical_screen",
-			CompositeMetadata: CompositeMetadata{
-				Video: MediaMetadata{Bitrate: 3718964, ColourPrimaries: "BT.709", Duration: 4567, Format: "HEVC", FrameRate: 30, Height: 854, MatrixCoefficients: "BT.709", Rotation: 0, TransferCharacteristics: "BT.709", Width: 480},
-				Audio: MediaMetadata{Bitrate: 178919, Channels: 2, Duration: 4575, Format: "AAC", SamplingRate: 44100},
-			},
-			Timelines: []string{},
-			Cover: CoverInfo{
-				Fileid:        coverFileID,
-				FileID:        coverFileID,
-				Height:        854,
-				Width:         480,
-				Frame:         FrameInfo{Ts: 0, IsUserSelect: false, IsUpload: false},
-				Stickers:      StickersInfo{Version: 2, Neptune: []string{}},
-				Fonts:         []string{},
-				ExtraInfoJson: "{}",
-			},
-			Chapters:        []string{},
-			ChapterSyncText: false,
-			Segments: SegmentsInfo{
-				Count:     1,
-				NeedSlice: false,
-				Items: []SegmentItem{
-					{
-						Mute:        0,
-						Speed:       1,
-						Start:       0,
-						Duration:    4.567,
-						Transcoded:  0,
-						MediaSource: 1,
-						OriginalMetadata: CompositeMetadata{
-							Video: MediaMetadata{Bitrate: 3718964, ColourPrimaries: "BT.709", Duration: 4567, Format: "HEVC", FrameRate: 30, Height: 854, MatrixCoefficients: "BT.709", Rotation: 0, TransferCharacteristics: "BT.709", Width: 480},
-							Audio: MediaMetadata{Bitrate: 178919, Channels: 2, Duration: 4575, Format: "AAC", SamplingRate: 44100},
-						},
-					},
-				},
-			},
-		},
+// publishNote 提交发布笔记请求，返回笔记 ID
+func (c *XhsClient) publishNote(ctx context.Context, payload PublishNotePayload) (string, error) {
+	var result struct {
+		BaseResponse
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.postJSON(ctx, "/web_api/sns/v2/note", payload, &result); err != nil {
+		return "", err
 	}
+	if err := result.check("publish note"); err != nil {
+		return "", err
+	}
+	return result.Data.ID, nil
+}
 
-	resp, err := c.client.R().
-		SetBody(payload). // 使用 SetBody 发送 JSON 数据
-		Post(publishURL)
+// imageSize 返回图片的宽高，webp 由 imageSizeWebP 解析，其余格式使用标准库解码
+func imageSize(data []byte, contentType string) (int, int, error) {
+	switch contentType {
+	case "image/webp":
+		return imageSizeWebP(data)
+	case "image/jpeg", "image/png", "image/gif":
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return 0, 0, err
+		}
+		return cfg.Width, cfg.Height, nil
+	default:
+		return 0, 0, fmt.Errorf("不支持的图片格式: %s", contentType)
+	}
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to publish note: %w", err)
+var errInvalidWebP = errors.New("invalid webp")
+
+// imageSizeWebP 从 VP8/VP8L/VP8X 块头中读取 webp 图片的宽高
+func imageSizeWebP(data []byte) (int, int, error) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, errInvalidWebP
 	}
-	if resp.IsError() {
-		return nil, fmt.Errorf("publish note request failed with status: %s, body: %s", resp.Status(), resp.String())
+	switch string(data[12:16]) {
+	case "VP8 ":
+		w := binary.LittleEndian.Uint16(data[26:28]) & 0x3fff
+		h := binary.LittleEndian.Uint16(data[28:30]) & 0x3fff
+		return int(w), int(h), nil
+	case "VP8L":
+		if data[20] != 0x2f {
+			return 0, 0, errInvalidWebP
+		}
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, nil
+	case "VP8X":
+		w := uint32(data[24]) | uint32(data[25])<<8 | uint32(data[26])<<16
+		h := uint32(data[27]) | uint32(data[28])<<8 | uint32(data[29])<<16
+		return int(w) + 1, int(h) + 1, nil
+	default:
+		return 0, 0, errInvalidWebP
 	}
-
-	fmt.Println("Successfully published note!")
-	fmt.Println("Response:", resp.String())
-	return resp, nil
 }
 
-func (c *XhsClient) test() {
-	// 创建一个 resty 客户端用于后续的 API 请求
-	client := resty.New()
+// PublishVideoNote 上传视频与封面并发布视频笔记，返回笔记 ID
+// 视频需为 MP4/MOV，宽高、时长、编码等元数据从文件中解析；coverPath 为封面图片
+func (c *XhsClient) PublishVideoNote(ctx context.Context, videoPath, coverPath, title, desc string, opts *NotePublishOptions) (string, error) {
+	info, err := media.ProbeFile(videoPath)
+	if err != nil {
+		return "", fmt.Errorf("解析视频元数据失败: %w", err)
+	}
+	if info.Video == nil {
+		return "", fmt.Errorf("%s 中没有视频轨道", videoPath)
+	}
+	common, err := c.noteCommon(ctx, NoteTypeVideo, title, desc, opts)
+	if err != nil {
+		return "", err
+	}
 
-	// 1. 获取上传许可
-	permitResp, err := c.GetUploadPermit(client)
+	file, err := os.Open(videoPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
 	if err != nil {
-		panic(err)
+		return "", err
+	}
+	contentType := "video/mp4"
+	if strings.EqualFold(filepath.Ext(videoPath), ".mov") {
+		contentType = "video/quicktime"
 	}
-	// 在实际应用中，你需要从 permitResp 中解析出上传所需的信息
-	// 例如: fileID := gjson.Get(permitResp.String(), "data.uploadTempPermits.0.fileIds.0").String()
-	fmt.Println("Permit Response Status:", permitResp.Status())
 
-	// 2. 上传文件
-	// 占位符：将 "path/to/your/image.png" 替换为你的文件路径
-	uploadResp, err := c.UploadFile(permitResp, "path/to/your/image.png")
+	permit, err := c.GetUploadPermit(ctx, uploadSceneVideo, 1)
 	if err != nil {
-		panic(err)
-	}
-	// 从 getUploadPermit 的响应中获取 fileId，而不是 uploadResp 的响应头
-	// 这是一个占位符，你需要用真实逻辑替换
-	uploadedFileID := "spectrum/wc7dm3ayWRWG0399QncgK1AAjBMdETEUhZReftWx49SAtrQ" // 从 getUploadPermit 响应中解析
-	fmt.Println("Upload Response Status:", uploadResp.Status())
-	fmt.Printf("Uploaded File ID: %s\n", uploadedFileID)
-
-	// 3. 发布笔记
-	// 占位符：请替换成你自己的笔记标题和描述
-	noteTitle := "我的视频笔记标题"
-	noteDesc := "这是通过 Go 脚本发布的视频笔记描述！ #Go #自动化"
-	// 占位符：videoFileID 和 coverFileID 都来自第一步 getUploadPermit 的返回，你需要从该响应中解析
-	// 通常，你会为视频和封面分别调用 getUploadPermit，或者在一次调用中获取两个许可
-	videoFileID := "spectrum/ovWswFtPGlBalii4HplaQzFL_DsPYOO2Mr64dstTmR8EHUg" // 示例 video file ID
-	coverFileID := "spectrum/wc7dm3ayWRWG0399QncgK1AAjBMdETEUhZReftWx49SAtrQ" // 示例 cover file ID
-
-	publishResp, err := c.PublishNote(noteTitle, noteDesc, videoFileID, coverFileID)
+		return "", err
+	}
+	videoFileID := permit.FileIDs[0]
+	if err := c.uploadFile(ctx, permit, videoFileID, file, stat.Size(), contentType); err != nil {
+		return "", fmt.Errorf("%s: %w", videoPath, err)
+	}
+
+	covers, err := c.uploadImages(ctx, []string{coverPath})
 	if err != nil {
-		panic(err)
+		return "", fmt.Errorf("上传封面失败: %w", err)
+	}
+
+	return c.publishNote(ctx, PublishNotePayload{
+		Common:    common,
+		VideoInfo: videoInfo(videoFileID, info, covers[0]),
+	})
+}
+
+// videoInfo 根据解析出的视频元数据构建 video_info
+func videoInfo(fileID string, info *media.Info, cover NoteImageInfo) *VideoInfo {
+	metadata := compositeMetadata(info)
+	width, height := info.Video.DisplaySize()
+	duration := info.Duration
+	if duration == 0 {
+		duration = info.Video.Duration
+	}
+
+	return &VideoInfo{
+		Fileid:            fileID,
+		FileID:            fileID,
+		FormatWidth:       width,
+		FormatHeight:      height,
+		VideoPreviewType:  videoPreviewType(width, height),
+		CompositeMetadata: metadata,
+		Timelines:         []string{},
+		Cover: CoverInfo{
+			Fileid:        cover.FileID,
+			FileID:        cover.FileID,
+			Height:        cover.Height,
+			Width:         cover.Width,
+			Frame:         FrameInfo{Ts: 0, IsUserSelect: false, IsUpload: true},
+			Stickers:      StickersInfo{Version: 2, Neptune: []string{}},
+			Fonts:         []string{},
+			ExtraInfoJson: "{}",
+		},
+		Chapters:        []string{},
+		ChapterSyncText: false,
+		Segments: SegmentsInfo{
+			Count:     1,
+			NeedSlice: false,
+			Items: []SegmentItem{
+				{
+					Mute:             0,
+					Speed:            1,
+					Start:            0,
+					Duration:         duration.Seconds(),
+					Transcoded:       0,
+					MediaSource:      1,
+					OriginalMetadata: metadata,
+				},
+			},
+		},
+	}
+}
+
+// 视频预览类型，由显示宽高决定
+const (
+	videoPreviewVertical   = "full_vertical_screen"
+	videoPreviewHorizontal = "full_horizontal_screen"
+	videoPreviewSquare     = "full_square_screen"
+)
+
+// videoPreviewType 根据旋转后的显示宽高返回竖屏、横屏或方形预览类型
+func videoPreviewType(width, height int) string {
+	switch {
+	case height > width:
+		return videoPreviewVertical
+	case width > height:
+		return videoPreviewHorizontal
+	default:
+		return videoPreviewSquare
+	}
+}
+
+// compositeMetadata 将解析出的音视频轨道信息转换为接口使用的 composite_metadata，时长单位为毫秒
+func compositeMetadata(info *media.Info) CompositeMetadata {
+	v := info.Video
+	m := CompositeMetadata{
+		Video: MediaMetadata{
+			Bitrate:                 v.Bitrate,
+			ColourPrimaries:         v.ColourPrimaries,
+			Duration:                int(v.Duration.Milliseconds()),
+			Format:                  v.Codec,
+			FrameRate:               int(math.Round(v.FrameRate)),
+			Height:                  v.Height,
+			MatrixCoefficients:      v.MatrixCoefficients,
+			Rotation:                v.Rotation,
+			TransferCharacteristics: v.TransferCharacteristics,
+			Width:                   v.Width,
+		},
+	}
+	if a := info.Audio; a != nil {
+		m.Audio = MediaMetadata{
+			Bitrate:      a.Bitrate,
+			Channels:     a.Channels,
+			Duration:     int(a.Duration.Milliseconds()),
+			Format:       a.Codec,
+			SamplingRate: a.SampleRate,
+		}
 	}
-	fmt.Println("Publish Response Status:", publishResp.Status())
+	return m
 }
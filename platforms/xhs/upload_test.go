@@ -0,0 +1,75 @@
+package xhs
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// webpFile 构建只含一个块头的 webp 文件，payload 为块内容
+func webpFile(chunk string, payload []byte) []byte {
+	b := []byte("RIFF")
+	b = binary.LittleEndian.AppendUint32(b, uint32(12+len(payload)))
+	b = append(b, "WEBP"...)
+	b = append(b, chunk...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(payload)))
+	return append(b, payload...)
+}
+
+func TestImageSizeWebP(t *testing.T) {
+	// VP8: 3 字节帧标记 + 起始码 9d 01 2a + 14 位宽高 (高 2 位为缩放)
+	vp8 := []byte{0x30, 0x01, 0x00, 0x9d, 0x01, 0x2a}
+	vp8 = binary.LittleEndian.AppendUint16(vp8, 1080|0xc000)
+	vp8 = binary.LittleEndian.AppendUint16(vp8, 1440)
+
+	// VP8L: 签名 0x2f + 14 位 (宽-1)、14 位 (高-1)
+	vp8l := []byte{0x2f}
+	vp8l = binary.LittleEndian.AppendUint32(vp8l, uint32(799)|uint32(599)<<14)
+	vp8l = append(vp8l, make([]byte, 8)...) // 图像数据
+
+	// VP8X: 4 字节标志 + 24 位 (宽-1)、24 位 (高-1)
+	vp8x := []byte{0x10, 0, 0, 0, 0x3f, 0x42, 0x0f, 0x1f, 0x4e, 0x00}
+
+	tests := []struct {
+		name          string
+		data          []byte
+		width, height int
+	}{
+		{"VP8", webpFile("VP8 ", vp8), 1080, 1440},
+		{"VP8L", webpFile("VP8L", vp8l), 800, 600},
+		{"VP8X", webpFile("VP8X", vp8x), 0x0f423f + 1, 0x4e1f + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h, err := imageSizeWebP(tt.data)
+			if err != nil {
+				t.Fatalf("imageSizeWebP() error = %v", err)
+			}
+			if w != tt.width || h != tt.height {
+				t.Errorf("imageSizeWebP() = %dx%d, want %dx%d", w, h, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestImageSizeWebPInvalid(t *testing.T) {
+	vp8x := webpFile("VP8X", []byte{0x10, 0, 0, 0, 0x3f, 0x42, 0x0f, 0x1f, 0x4e, 0x00})
+	badVP8L := webpFile("VP8L", []byte{0x00, 0, 0, 0, 0})
+	notWebP := append([]byte{}, vp8x...)
+	copy(notWebP[8:12], "WAVE")
+
+	tests := map[string][]byte{
+		"empty":         nil,
+		"truncated":     vp8x[:29],
+		"not riff":      append([]byte("RIFX"), vp8x[4:]...),
+		"not webp":      notWebP,
+		"bad vp8l sig":  append(badVP8L, make([]byte, 8)...),
+		"unknown chunk": webpFile("ALPH", make([]byte, 10)),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := imageSizeWebP(data); err != errInvalidWebP {
+				t.Errorf("imageSizeWebP() error = %v, want errInvalidWebP", err)
+			}
+		})
+	}
+}
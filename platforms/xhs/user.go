@@ -5,10 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"regexp"
 	"strings"
-
-	"github.com/PuerkitoBio/goquery"
 )
 
 const (
@@ -17,56 +14,119 @@ const (
 	userAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36"
 )
 
-// UserProfile 代表完整的用户个人信息，包括基本信息和笔记
-type UserProfile struct {
-	User  UserDetail `json:"user"`
-	Notes []Note     `json:"notes"`
+// 用户性别
+const (
+	GenderMale   = 0
+	GenderFemale = 1
+)
+
+// UserTag 用户主页展示的标签，如所在地、职业
+type UserTag struct {
+	TagType string `json:"tag_type"`
+	Name    string `json:"name"`
 }
 
-// UserDetail 代表从HTML中解析出的用户详细信息
-type UserDetail map[string]interface{}
+// UserProfile 从用户主页解析出的用户信息，数量字段为页面展示文本 (如 "10万+")
+type UserProfile struct {
+	UserID     string
+	Nickname   string
+	RedID      string
+	Desc       string
+	Gender     int
+	IPLocation string
+	Avatar     string
+	// Follows 关注数
+	Follows string
+	// Fans 粉丝数
+	Fans string
+	// Interactions 获赞与收藏数
+	Interactions string
+	Tags         []UserTag
+	// Notes 主页首屏展示的笔记
+	Notes []NoteCard
+}
 
 // Note 代表用户的单条笔记
 type Note map[string]interface{}
 
-// getUserDetail 获取小红书用户详细信息
-func (c *XhsClient) GetUserDetail(ctx context.Context, userID string) (UserDetail, error) {
-	reqURL := fmt.Sprintf("%s/user/profile/%s", webHost, userID)
-	resp, err := c.client.R().
-		SetContext(ctx).
-		SetHeader("User-Agent", userAgent).
-		Get(reqURL)
-
-	if err != nil {
-		return nil, fmt.Errorf("请求用户详情页失败: %w", err)
-	}
-	if resp.IsError() {
-		return nil, fmt.Errorf("请求用户详情页返回错误状态: %s", resp.Status())
+// GetUserDetail 获取小红书用户主页信息
+func (c *XhsClient) GetUserDetail(ctx context.Context, userID string) (*UserProfile, error) {
+	pageURL := fmt.Sprintf("%s/user/profile/%s", webHost, userID)
+	if token := c.xsecToken(userID); token != "" {
+		pageURL += "?" + url.Values{"xsec_token": {token}, "xsec_source": {xsecSourcePCFeed}}.Encode()
 	}
-
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(resp.String()))
+	state, err := c.fetchInitialState(ctx, pageURL)
 	if err != nil {
-		return nil, fmt.Errorf("解析HTML失败: %w", err)
+		return nil, fmt.Errorf("获取用户详情失败: %w", err)
 	}
 
-	re := regexp.MustCompile(`window\.__INITIAL_STATE__=`)
-	scriptContent := doc.Find("script").FilterFunction(func(i int, s *goquery.Selection) bool {
-		return re.MatchString(s.Text())
-	}).First().Text()
-
-	if scriptContent == "" {
-		return nil, fmt.Errorf("在HTML中未找到 __INITIAL_STATE__")
+	pageData := lookup(state, "user", "userPageData")
+	if pageData == nil {
+		return nil, fmt.Errorf("页面中未找到用户 %s", userID)
+	}
+	var data struct {
+		BasicInfo struct {
+			Nickname   string `json:"nickname"`
+			RedID      string `json:"red_id"`
+			Desc       string `json:"desc"`
+			Gender     int    `json:"gender"`
+			IPLocation string `json:"ip_location"`
+			Images     string `json:"images"`
+		} `json:"basic_info"`
+		Interactions []struct {
+			Type  string `json:"type"`
+			Count string `json:"count"`
+		} `json:"interactions"`
+		Tags []UserTag `json:"tags"`
+	}
+	if err := decodeState(pageData, &data); err != nil {
+		return nil, fmt.Errorf("解析用户数据失败: %w", err)
 	}
 
-	jsonStr := strings.TrimPrefix(scriptContent, "window.__INITIAL_STATE__=")
-	jsonStr = strings.ReplaceAll(jsonStr, "undefined", "null")
-
-	var data UserDetail
-	if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
-		return nil, fmt.Errorf("解析JSON失败: %w", err)
+	profile := &UserProfile{
+		UserID:     userID,
+		Nickname:   data.BasicInfo.Nickname,
+		RedID:      data.BasicInfo.RedID,
+		Desc:       data.BasicInfo.Desc,
+		Gender:     data.BasicInfo.Gender,
+		IPLocation: data.BasicInfo.IPLocation,
+		Avatar:     data.BasicInfo.Images,
+		Tags:       data.Tags,
+	}
+	for _, it := range data.Interactions {
+		switch it.Type {
+		case "follows":
+			profile.Follows = it.Count
+		case "fans":
+			profile.Fans = it.Count
+		case "interaction":
+			profile.Interactions = it.Count
+		}
 	}
 
-	return data, nil
+	// user.notes 按标签页分组，第一组为用户发布的笔记
+	if groups, ok := lookup(state, "user", "notes").([]interface{}); ok && len(groups) > 0 {
+		var items []struct {
+			ID        string   `json:"id"`
+			XsecToken string   `json:"xsec_token"`
+			NoteCard  NoteCard `json:"note_card"`
+		}
+		if err := decodeState(groups[0], &items); err != nil {
+			return nil, fmt.Errorf("解析用户笔记失败: %w", err)
+		}
+		for _, item := range items {
+			card := item.NoteCard
+			if card.NoteID == "" {
+				card.NoteID = item.ID
+			}
+			if card.XsecToken == "" {
+				card.XsecToken = item.XsecToken
+			}
+			c.setXsecToken(card.NoteID, card.XsecToken)
+			profile.Notes = append(profile.Notes, card)
+		}
+	}
+	return profile, nil
 }
 
 // getUserNotes 获取用户发布的笔记列表
@@ -83,16 +143,16 @@ func (c *XhsClient) GetUserNotes(ctx context.Context, userID string, offset, lim
 			"cursor":        cursor,
 			"user_id":       userID,
 			"image_formats": "jpg",
-			// xsec_token=ABn830XCOxiqnEyuW9NzS0hmuNr9Se3HJ3v-pZFRItuHo%3D&xsec_source=pc_feed
-			// "xsec_token":  "ABn830XCOxiqnEyuW9NzS0hmuNr9Se3HJ3v-pZFRItuHo=",
-			// "xsec_source": "pc_feed",
+		}
+		if token := c.xsecToken(userID); token != "" {
+			params["xsec_token"] = token
+			params["xsec_source"] = xsecSourcePCFeed
 		}
 
 		fullURL := fmt.Sprintf("%s%s", apiHost, apiURL)
 		resp, err := c.client.R().
 			SetContext(ctx).
 			SetQueryParamsFromValues(toURLValues(params)).
-			
 			Get(fullURL)
 
 		if err != nil {
@@ -114,6 +174,11 @@ func (c *XhsClient) GetUserNotes(ctx context.Context, userID string, offset, lim
 			return nil, fmt.Errorf("解析笔记API响应失败: %w", err)
 		}
 
+		for _, note := range result.Data.Notes {
+			noteID, _ := note["note_id"].(string)
+			token, _ := note["xsec_token"].(string)
+			c.setXsecToken(noteID, token)
+		}
 		allNotes = append(allNotes, result.Data.Notes...)
 		cursor = result.Data.Cursor
 		hasMore = result.Data.HasMore
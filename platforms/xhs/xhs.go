@@ -1,20 +1,45 @@
 package xhs
 
 import (
+	"context"
 	"crawler-sdk/internal/crypto/xhs"
 	"crawler-sdk/pkg/http"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	"resty.dev/v3"
 )
 
+// BaseResponse 是网页端 API 响应的通用字段
+type BaseResponse struct {
+	Code    int    `json:"code"`
+	Success bool   `json:"success"`
+	Msg     string `json:"msg"`
+}
+
+// check 在 success 为 false 时返回错误
+func (r *BaseResponse) check(action string) error {
+	if r.Success {
+		return nil
+	}
+	return fmt.Errorf("%s failed, status code: %d, msg: %s", action, r.Code, r.Msg)
+}
+
 type XhsClient struct {
 	client       *resty.Client
 	UploadClient *resty.Client
+
+	// xsecTokens 缓存列表接口返回的 xsec_token，键为笔记或用户 ID
+	xsecTokens *xsecCache
+
+	// feeds 首页各推荐频道的翻页状态
+	feedMu sync.Mutex
+	feeds  map[FeedCategory]*feedState
 }
 
 // New 创建一个新的小红书客户端实例
@@ -23,16 +48,87 @@ func New(cookie string) *XhsClient {
 	c := &XhsClient{
 		client:       http.NewClient(cookie),
 		UploadClient: resty.New(),
+		xsecTokens:   newXsecCache(xsecTokenCacheSize),
+		feeds:        make(map[FeedCategory]*feedState),
 	}
 	c.client.AddRequestMiddleware(headers)
 	c.client.AddRequestMiddleware(SignXYS)
 	// c.client.AddRequestMiddleware(SignXS)
 	c.client.AddRequestMiddleware(SignXSC)
 	c.client.AddRequestMiddleware(SignTraceID)
-	c.UploadClient.AddRequestMiddleware(QSign)
+	// rawContentLength 需在 PrepareRequestMiddleware 创建 RawRequest 之后执行
+	c.UploadClient.SetRequestMiddlewares(
+		QSign,
+		resty.PrepareRequestMiddleware,
+		rawContentLength,
+	)
 	return c
 }
 
+// xsecToken 返回缓存的 xsec_token，不存在时返回空字符串
+func (c *XhsClient) xsecToken(id string) string {
+	return c.xsecTokens.get(id)
+}
+
+// setXsecToken 缓存 id 对应的 xsec_token，供之后的详情请求使用
+func (c *XhsClient) setXsecToken(id, token string) {
+	if id == "" || token == "" {
+		return
+	}
+	c.xsecTokens.set(id, token)
+}
+
+// postJSON 以 JSON 请求体调用网页端 API 并将响应解码到 result
+// 请求体先序列化为 []byte，保证 SignXYS 签名的内容与实际发送的一致
+func (c *XhsClient) postJSON(ctx context.Context, path string, payload, result interface{}) error {
+	return c.postJSONWithHeaders(ctx, path, nil, payload, result)
+}
+
+// postJSONWithHeaders 与 postJSON 相同，额外设置 hdrs 中的请求头，如创作者中心页面的 Origin 与 Referer
+func (c *XhsClient) postJSONWithHeaders(ctx context.Context, path string, hdrs map[string]string, payload, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeaders(hdrs).
+		SetHeader("Content-Type", "application/json;charset=UTF-8").
+		SetBody(body).
+		Post(apiHost + path)
+	if err != nil {
+		return fmt.Errorf("请求 %s 失败: %w", path, err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("请求 %s 返回错误状态: %s, body: %s", path, resp.Status(), resp.String())
+	}
+	if err := json.Unmarshal([]byte(resp.String()), result); err != nil {
+		return fmt.Errorf("解析 %s 响应失败: %w", path, err)
+	}
+	return nil
+}
+
+// getJSON 以 GET 调用 rawURL 并将响应解码到 result，referer 为空时不设置 Referer
+func (c *XhsClient) getJSON(ctx context.Context, rawURL, referer string, params map[string]string, result interface{}) error {
+	req := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(params)
+	if referer != "" {
+		req.SetHeader("Referer", referer)
+	}
+	resp, err := req.Get(rawURL)
+	if err != nil {
+		return fmt.Errorf("请求 %s 失败: %w", rawURL, err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("请求 %s 返回错误状态: %s, body: %s", rawURL, resp.Status(), resp.String())
+	}
+	if err := json.Unmarshal([]byte(resp.String()), result); err != nil {
+		return fmt.Errorf("解析 %s 响应失败: %w", rawURL, err)
+	}
+	return nil
+}
+
 func headers(c *resty.Client, req *resty.Request) error {
 	fmt.Println("headers")
 	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8,en-GB;q=0.7,en-US;q=0.6")
@@ -43,7 +139,10 @@ func headers(c *resty.Client, req *resty.Request) error {
 	req.Header.Set("sec-ch-ua-mobile", "?0")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36 Edg/139.0.0.0")
 	req.Header.Set("Accept", "application/json, text/plain, */*")
-	req.Header.Set("Origin", "https://www.xiaohongshu.com")
+	// 创作者中心等页面发起的请求会自行设置 Origin
+	if req.Header.Get("Origin") == "" {
+		req.Header.Set("Origin", webHost)
+	}
 	req.Header.Set("Sec-Fetch-Site", "same-site")
 	req.Header.Set("Sec-Fetch-Mode", "cors")
 	req.Header.Set("Sec-Fetch-Dest", "empty")
@@ -51,28 +150,48 @@ func headers(c *resty.Client, req *resty.Request) error {
 }
 
 func SignXYS(c *resty.Client, req *resty.Request) error {
-	fmt.Println("SignXYS")
 	cookie := c.Header().Get("Cookie")
 	u, err := url.Parse(req.URL)
 	if err != nil {
 		return err
 	}
-	fmt.Print(u)
 	a1 := getCookieValue(cookie, "a1")
-	// xsc := xhs.XYS(req.URL, a1)
-	fmt.Println(req.Method)
-	fmt.Println(u.Path)
-	fmt.Println(a1)
-	fmt.Println("xhs-pc-web")
-	fmt.Println(req.QueryParams)
-	xsc := xhs.Sign(req.Method, u.Path, a1, "xhs-pc-web", req.QueryParams)
-	req.Header.Set("X-s", xsc)
+	var xs string
+	if req.Method == resty.MethodPost && req.Body != nil {
+		// POST 请求对 JSON 请求体签名，请求体需以 []byte 或 string 传入以保证与实际发送的内容一致
+		body, err := requestBody(req.Body)
+		if err != nil {
+			return err
+		}
+		xs = xhs.SignJSON(u.Path, a1, "xhs-pc-web", body)
+	} else {
+		xs = xhs.Sign(req.Method, u.Path, a1, "xhs-pc-web", req.QueryParams)
+	}
+	req.Header.Set("X-s", xs)
 	ts := fmt.Sprintf("%d", time.Now().UnixMilli())
 	req.Header.Set("X-t", ts)
 
 	return nil
 }
 
+// requestBody 返回用于签名的请求体
+func requestBody(body any) ([]byte, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("JSON编码失败: %w", err)
+		}
+		return data, nil
+	}
+}
+
 func SignXS(c *resty.Client, req *resty.Request) error {
 	fmt.Println("SignXS")
 	encryptor := xhs.NewXsEncrypt()
@@ -129,16 +248,32 @@ func SignTraceID(c *resty.Client, req *resty.Request) error {
 	return nil
 }
 
+// QSign 为上传请求计算 COS 签名
+// 上传凭证由 x-cos-security-token 携带，网页端签名时 SecretId 与 SecretKey 固定为 "null"
+// 请求体为 io.Reader 时需设置 Content-Length 请求头，签名使用该值
 func QSign(c *resty.Client, req *resty.Request) error {
-	cookie := c.Header().Get("Cookie")
-	a1 := getCookieValue(cookie, "a1")
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return err
+	}
+	contentLength := req.Header.Get("Content-Length")
+	if contentLength == "" {
+		body, err := requestBody(req.Body)
+		if err != nil {
+			return err
+		}
+		contentLength = strconv.Itoa(len(body))
+	}
 	q, err := xhs.GetQSignAuth(xhs.QSignAuthOptions{
-		SecretId:  a1,
-		SecretKey: a1,
+		SecretId:  "null",
+		SecretKey: "null",
 		Method:    req.Method,
-		Pathname:  req.URL,
+		Pathname:  u.Path,
 		Query:     map[string]string{},
-		Headers:   map[string]string{},
+		Headers: map[string]string{
+			"Content-Length": contentLength,
+			"Host":           u.Host,
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("获取QSignAuth失败: %w", err)
@@ -146,3 +281,19 @@ func QSign(c *resty.Client, req *resty.Request) error {
 	req.Header.Set("Authorization", q)
 	return nil
 }
+
+// rawContentLength 将 Content-Length 请求头写入 RawRequest
+// net/http 只会为内存中的请求体推断长度，文件等流式请求体否则会以 chunked 方式发送，与签名的长度不一致
+func rawContentLength(c *resty.Client, req *resty.Request) error {
+	if req.RawRequest == nil || req.RawRequest.ContentLength > 0 {
+		return nil
+	}
+	if v := req.Header.Get("Content-Length"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("无效的 Content-Length: %s", v)
+		}
+		req.RawRequest.ContentLength = n
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package xhs
+
+import (
+	"container/list"
+	"sync"
+)
+
+// xsecTokenCacheSize xsec_token 缓存的最大条目数，超出后淘汰最久未使用的条目
+const xsecTokenCacheSize = 10000
+
+// xsecCache 按最近使用淘汰的 xsec_token 缓存，避免长时间采集时无限增长
+type xsecCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // 元素为 *xsecEntry，表头为最近使用
+	items map[string]*list.Element
+}
+
+type xsecEntry struct {
+	id    string
+	token string
+}
+
+func newXsecCache(size int) *xsecCache {
+	return &xsecCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// get 返回 id 对应的 xsec_token，不存在时返回空字符串
+func (c *xsecCache) get(id string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[id]
+	if !ok {
+		return ""
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*xsecEntry).token
+}
+
+// set 写入或更新 id 对应的 xsec_token
+func (c *xsecCache) set(id, token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[id]; ok {
+		el.Value.(*xsecEntry).token = token
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[id] = c.order.PushFront(&xsecEntry{id: id, token: token})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*xsecEntry).id)
+	}
+}
@@ -0,0 +1,48 @@
+package xhs
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestXsecCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newXsecCache(2)
+	c.set("a", "ta")
+	c.set("b", "tb")
+	c.get("a") // a 成为最近使用
+	c.set("c", "tc")
+
+	if got := c.get("b"); got != "" {
+		t.Errorf("get(b) = %q, want evicted", got)
+	}
+	if c.get("a") != "ta" || c.get("c") != "tc" {
+		t.Errorf("get(a), get(c) = %q, %q", c.get("a"), c.get("c"))
+	}
+
+	c.set("a", "ta2")
+	c.set("d", "td")
+	if c.get("a") != "ta2" || c.get("c") != "" || c.order.Len() != 2 || len(c.items) != 2 {
+		t.Errorf("after update: a = %q, c = %q, len = %d/%d", c.get("a"), c.get("c"), c.order.Len(), len(c.items))
+	}
+}
+
+func TestXsecCacheBounded(t *testing.T) {
+	c := newXsecCache(100)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				id := strconv.Itoa(w) + "-" + strconv.Itoa(i)
+				c.set(id, "t")
+				c.get(id)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if c.order.Len() != 100 || len(c.items) != 100 {
+		t.Errorf("cache holds %d/%d entries, want 100", c.order.Len(), len(c.items))
+	}
+}